@@ -0,0 +1,192 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"micemen/game"
+)
+
+// Search depths for the alpha-beta bot, in moves by either player
+const (
+	DefaultSearchDepth = 4
+	maxSearchDepth     = 8
+)
+
+// AlphaBetaBot looks a fixed number of moves ahead with alpha-beta pruning and picks the
+// move whose worst case scores best by Evaluate
+type AlphaBetaBot struct {
+	depth int
+
+	// Table remembers positions searched before, including on earlier moves. Bots can
+	// share a table; nil searches without one.
+	Table *TranspositionTable
+}
+
+// NewAlphaBetaBot creates an alpha-beta bot searching the given number of moves ahead.
+// Deeper searches play better but take longer; depth should be from 1 to 8.
+func NewAlphaBetaBot(depth int) *AlphaBetaBot {
+	return &AlphaBetaBot{depth: depth, Table: NewTranspositionTable(DefaultTableSize)}
+}
+
+// Name returns the bot's name
+func (b *AlphaBetaBot) Name() string {
+	return fmt.Sprintf("alphabeta:%d", b.depth)
+}
+
+// ChooseMove searches from a copy of the current position and returns the best move found
+func (b *AlphaBetaBot) ChooseMove(g game.Game) (game.Move, error) {
+	state := g.GetState()
+	player := state.CurrentPlayer
+
+	moves := b.orderedMoves(state)
+	if len(moves) == 0 {
+		return game.Move{}, fmt.Errorf("%s has no legal moves", player)
+	}
+
+	best := moves[0]
+	alpha, beta := math.MinInt+1, math.MaxInt
+	for _, move := range moves {
+		child, err := game.Apply(state, move)
+		if err != nil {
+			return game.Move{}, err
+		}
+
+		score := -b.search(child, player.Opponent(), b.depth-1, -beta, -alpha)
+		if score > alpha {
+			alpha = score
+			best = move
+		}
+	}
+	return best, nil
+}
+
+// MoveScore is a move's search score for the player making it
+type MoveScore struct {
+	Move  game.Move
+	Score int
+}
+
+// Mice returns the score in mice home, the unit Evaluate's weights are based on
+func (s MoveScore) Mice() float64 {
+	return float64(s.Score) / escapedWeight
+}
+
+// Forced returns 1 if the search found a forced win after the move, -1 if it found a
+// forced loss, and 0 otherwise
+func (s MoveScore) Forced() int {
+	switch {
+	case s.Score >= winScore-maxSearchDepth:
+		return 1
+	case s.Score <= -winScore+maxSearchDepth:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// ScoreMoves scores every legal move in the position, in LegalMoves order. Each move gets
+// a search of its own, so its score is exact rather than just bad enough to rule it out.
+// It gives up with the context's error once the context is cancelled.
+func (b *AlphaBetaBot) ScoreMoves(ctx context.Context, state game.GameState) ([]MoveScore, error) {
+	player := state.CurrentPlayer
+	moves := game.LegalMoves(state)
+	scores := make([]MoveScore, 0, len(moves))
+	for _, move := range moves {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		child, err := game.Apply(state, move)
+		if err != nil {
+			continue
+		}
+		score := -b.search(child, player.Opponent(), b.depth-1, math.MinInt+1, math.MaxInt)
+		scores = append(scores, MoveScore{Move: move, Score: score})
+	}
+	return scores, nil
+}
+
+// search returns the negamax score of a position for the player to move, looking depth
+// more moves ahead. Scores outside [alpha, beta] are cut off early.
+func (b *AlphaBetaBot) search(state game.GameState, player game.PlayerColor, depth, alpha, beta int) int {
+	if state.GameOver {
+		// Prefer quick wins and slow losses
+		score := Evaluate(state, player)
+		if score > 0 {
+			return score + depth
+		}
+		return score - depth
+	}
+	if depth <= 0 {
+		return Evaluate(state, player)
+	}
+
+	// A deep enough earlier search of the position may settle it or narrow the window
+	if b.Table != nil {
+		if entry, ok := b.Table.Lookup(state.Hash); ok && entry.Depth >= depth {
+			switch entry.Bound {
+			case BoundExact:
+				return entry.Score
+			case BoundLower:
+				alpha = max(alpha, entry.Score)
+			case BoundUpper:
+				beta = min(beta, entry.Score)
+			}
+			if alpha >= beta {
+				return entry.Score
+			}
+		}
+	}
+
+	windowStart := alpha
+	best, bestMove := math.MinInt+1, game.Move{}
+	for _, move := range b.orderedMoves(state) {
+		child, err := game.Apply(state, move)
+		if err != nil {
+			continue
+		}
+
+		score := -b.search(child, player.Opponent(), depth-1, -beta, -alpha)
+		if score > best {
+			best, bestMove = score, move
+		}
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if b.Table != nil {
+		bound := BoundExact
+		if best <= windowStart {
+			bound = BoundUpper
+		} else if best >= beta {
+			bound = BoundLower
+		}
+		b.Table.Store(TTEntry{Hash: state.Hash, Depth: depth, Score: best, Bound: bound, Move: bestMove})
+	}
+	return best
+}
+
+// orderedMoves returns the legal moves with the best move from an earlier search first,
+// since searching the best move first lets alpha-beta cut off the most
+func (b *AlphaBetaBot) orderedMoves(state game.GameState) []game.Move {
+	moves := game.LegalMoves(state)
+	if b.Table == nil {
+		return moves
+	}
+
+	entry, ok := b.Table.Lookup(state.Hash)
+	if !ok {
+		return moves
+	}
+	for i, move := range moves {
+		if move == entry.Move {
+			copy(moves[1:i+1], moves[:i])
+			moves[0] = move
+			break
+		}
+	}
+	return moves
+}
@@ -0,0 +1,162 @@
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"micemen/game"
+)
+
+// winInOneMap has Red one mouse from winning. Shifting column 6 down drops the mouse to
+// the floor, where it can walk off the board; shifting it up leaves it facing a wall.
+const winInOneMap = "../testdata/win-in-one.map"
+
+// loadGame starts a game from a map file
+func loadGame(t *testing.T, path string) *game.MicemenGame {
+	t.Helper()
+	m, err := game.LoadMap(path)
+	if err != nil {
+		t.Fatalf("LoadMap failed: %v", err)
+	}
+	return game.NewGameFromState(m.State)
+}
+
+func TestAlphaBetaFindsWinningMove(t *testing.T) {
+	want := game.Move{Column: 5, Direction: game.DirectionDown}
+	for depth := 1; depth <= 4; depth++ {
+		g := loadGame(t, winInOneMap)
+		move, err := NewAlphaBetaBot(depth).ChooseMove(g)
+		if err != nil {
+			t.Fatalf("ChooseMove failed at depth %d: %v", depth, err)
+		}
+		if move != want {
+			t.Errorf("Depth %d search should play the winning move %v, got %v", depth, want, move)
+		}
+	}
+}
+
+func TestAlphaBetaLeavesGameAlone(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 3)
+	move, _ := NewRandomBotWithSeed(3).ChooseMove(g)
+	if err := g.ApplyMove(move); err != nil {
+		t.Fatalf("ApplyMove failed: %v", err)
+	}
+	before := g.GetState()
+	
+	if _, err := NewAlphaBetaBot(3).ChooseMove(g); err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	
+	after := g.GetState()
+	if after.CurrentPlayer != before.CurrentPlayer || after.LastShiftColumn != before.LastShiftColumn {
+		t.Errorf("Searching shouldn't change whose turn it is or the last shift")
+	}
+	for row := range before.Grid {
+		for col := range before.Grid[row] {
+			if before.Grid[row][col] != after.Grid[row][col] {
+				t.Fatalf("Searching shouldn't change the grid at row %d, column %d", row, col)
+			}
+		}
+	}
+	for i := range before.Mice {
+		if before.Mice[i] != after.Mice[i] {
+			t.Fatalf("Searching shouldn't move mouse %d", i)
+		}
+	}
+	if undo, redo := g.HistoryDepth(); undo != 1 || redo != 0 {
+		t.Errorf("Searching shouldn't touch the undo history, got %d undo and %d redo", undo, redo)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	state := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 5).GetState()
+	if red, blue := Evaluate(state, game.Red), Evaluate(state, game.Blue); red != -blue {
+		t.Errorf("Scores for the two players should be opposite, got %d and %d", red, blue)
+	}
+	
+	// A mouse home is worth more than any amount of walking
+	before := Evaluate(state, game.Red)
+	state.Escaped[game.Red]++
+	if after := Evaluate(state, game.Red); after <= before {
+		t.Errorf("Getting a mouse home should raise Red's score, got %d then %d", before, after)
+	}
+	
+	state.GameOver = true
+	state.Result = game.ResultAllMiceHome
+	state.Winner = game.Blue
+	if Evaluate(state, game.Blue) != winScore || Evaluate(state, game.Red) != -winScore {
+		t.Errorf("A won game should score as a win for Blue and a loss for Red")
+	}
+}
+
+func TestNewAlphaBetaBot(t *testing.T) {
+	bot, err := New("alphabeta")
+	if err != nil || bot.Name() != "alphabeta:4" {
+		t.Errorf("New(alphabeta) should search at the default depth, got %v, %v", bot, err)
+	}
+	if bot, err := New("alphabeta:2"); err != nil || bot.Name() != "alphabeta:2" {
+		t.Errorf("New(alphabeta:2) should search 2 moves deep, got %v, %v", bot, err)
+	}
+	for _, spec := range []string{"alphabeta:0", "alphabeta:9", "alphabeta:deep"} {
+		if _, err := New(spec); err == nil {
+			t.Errorf("New(%q) should fail", spec)
+		}
+	}
+}
+
+func TestScoreMovesAgreesWithChooseMove(t *testing.T) {
+	g := game.NewGameWithSeed(game.NewConfig(11, 7, 0), game.UniformGenerator{}, 5)
+	bot := NewAlphaBetaBot(3)
+	scores, err := bot.ScoreMoves(context.Background(), g.GetState())
+	if err != nil {
+		t.Fatalf("ScoreMoves failed: %v", err)
+	}
+	
+	legal := game.LegalMoves(g.GetState())
+	if len(scores) != len(legal) {
+		t.Fatalf("ScoreMoves should score all %d legal moves, got %d", len(legal), len(scores))
+	}
+	best := scores[0]
+	for i, score := range scores {
+		if score.Move != legal[i] {
+			t.Errorf("Score %d should be for %v, got %v", i, legal[i], score.Move)
+		}
+		if score.Score > best.Score {
+			best = score
+		}
+	}
+	
+	move, err := NewAlphaBetaBot(3).ChooseMove(g)
+	if err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	for _, score := range scores {
+		if score.Move == move && score.Score != best.Score {
+			t.Errorf("ChooseMove played %v scoring %d, but the best score is %d", move, score.Score, best.Score)
+		}
+	}
+}
+
+func TestScoreMovesFindsForcedWin(t *testing.T) {
+	g := loadGame(t, winInOneMap)
+	scores, err := NewAlphaBetaBot(2).ScoreMoves(context.Background(), g.GetState())
+	if err != nil {
+		t.Fatalf("ScoreMoves failed: %v", err)
+	}
+	for _, score := range scores {
+		win := score.Move == game.Move{Column: 5, Direction: game.DirectionDown}
+		if win != (score.Forced() == 1) {
+			t.Errorf("Only the winning move should be a forced win, but %v has Forced %d", score.Move, score.Forced())
+		}
+	}
+}
+
+func TestScoreMovesStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 2)
+	if scores, err := NewAlphaBetaBot(4).ScoreMoves(ctx, g.GetState()); err == nil || scores != nil {
+		t.Errorf("ScoreMoves should give up on a cancelled context, got %d scores and %v", len(scores), err)
+	}
+}
@@ -0,0 +1,65 @@
+// Package ai implements computer players for Micemen
+package ai
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"micemen/game"
+)
+
+// Bot chooses moves for the player to move
+type Bot interface {
+	// Name identifies the bot, e.g. on the command line and in game records
+	Name() string
+	// ChooseMove picks a legal move for the current player. It must not change the game.
+	ChooseMove(g game.Game) (game.Move, error)
+}
+
+// Human is the player name for a person at the keyboard rather than a bot
+const Human = "human"
+
+// BotNames lists the bots that New can create
+func BotNames() []string {
+	return []string{"random", "alphabeta[:depth]", "mcts[:time|playouts]"}
+}
+
+// New creates a bot from a player name such as "random". The name may be followed by a
+// colon and a setting for bots that take one. Use Human for a person instead.
+func New(spec string) (Bot, error) {
+	name, setting, _ := strings.Cut(spec, ":")
+	switch name {
+	case "random":
+		if setting != "" {
+			return nil, fmt.Errorf("bot %q takes no setting", name)
+		}
+		return NewRandomBot(), nil
+	case "alphabeta":
+		depth := DefaultSearchDepth
+		if setting != "" {
+			var err error
+			depth, err = strconv.Atoi(setting)
+			if err != nil || depth < 1 || depth > maxSearchDepth {
+				return nil, fmt.Errorf("alphabeta depth must be a number from 1 to %d, got %q", maxSearchDepth, setting)
+			}
+		}
+		return NewAlphaBetaBot(depth), nil
+	case "mcts":
+		if setting == "" {
+			return NewMCTSBot(DefaultThinkTime), nil
+		}
+		// A plain number is a playout count, anything else a thinking time such as 2s
+		if iterations, err := strconv.Atoi(setting); err == nil && iterations > 0 {
+			return NewMCTSBotWithIterations(iterations, time.Now().UnixNano()), nil
+		}
+		thinkTime, err := time.ParseDuration(setting)
+		if err != nil || thinkTime <= 0 {
+			return nil, fmt.Errorf("mcts setting must be a thinking time such as 2s or a number of playouts, got %q", setting)
+		}
+		return NewMCTSBot(thinkTime), nil
+	default:
+		return nil, fmt.Errorf("unknown bot %q", spec)
+	}
+}
@@ -0,0 +1,50 @@
+package ai
+
+import "micemen/game"
+
+// Evaluation weights, in points
+const (
+	escapedWeight  = 100 // Each mouse that made it home
+	progressWeight = 40  // A mouse crossing the whole board, scaled by how far it has come
+	trappedPenalty = 8   // A mouse facing a wall it can't walk through
+)
+
+// winScore is the score of a won game. Scores within maxSearchDepth of it are wins found by
+// the search, shifted so quicker wins score higher.
+const winScore = 1_000_000
+
+// Evaluate scores a position from the given player's point of view: positive when the
+// player is ahead. It counts mice already home, how far each mouse has walked toward the
+// far edge, and mice stuck behind a wall until a column shift frees them.
+func Evaluate(state game.GameState, player game.PlayerColor) int {
+	if state.Result == game.ResultAllMiceHome {
+		if state.Winner == player {
+			return winScore
+		}
+		return -winScore
+	}
+
+	var score [2]int
+	for _, p := range []game.PlayerColor{game.Red, game.Blue} {
+		score[p] = state.Escaped[p] * escapedWeight
+	}
+
+	width := state.Config.Width
+	for _, mouse := range state.Mice {
+		pos := mouse.Position
+
+		// Red walks right toward the last column, Blue left toward the first
+		progress, step := pos.Col, 1
+		if mouse.Player == game.Blue {
+			progress, step = width-1-pos.Col, -1
+		}
+		score[mouse.Player] += progress * progressWeight / width
+
+		next := game.Position{Row: pos.Row, Col: pos.Col + step}
+		if state.InBounds(next) && state.Grid[next.Row][next.Col] == game.Wall {
+			score[mouse.Player] -= trappedPenalty
+		}
+	}
+
+	return score[player] - score[player.Opponent()]
+}
@@ -0,0 +1,285 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"micemen/game"
+)
+
+// Monte Carlo tree search settings
+const (
+	DefaultThinkTime = time.Second
+	explorationConst = math.Sqrt2 // UCT exploration weight
+	maxPlayoutMoves  = 80         // Playouts longer than this are judged by Evaluate
+)
+
+// MCTSBot picks moves with Monte Carlo tree search (UCT): it plays many random games from
+// the current position, growing a tree toward the moves that win most often. Each worker
+// goroutine grows its own tree and the root statistics are added up at the end.
+type MCTSBot struct {
+	iterations int           // Playouts per move, or 0 to search for thinkTime
+	thinkTime  time.Duration // How long to search when iterations is 0
+	rng        *rand.Rand    // Seeds the workers' random playouts
+
+	// Workers is the number of goroutines searching in parallel
+	Workers int
+	// OnSearch, if set, is called with the statistics of every search
+	OnSearch func(stats SearchStats)
+}
+
+// NewMCTSBot creates an MCTS bot that thinks for the given time per move
+func NewMCTSBot(thinkTime time.Duration) *MCTSBot {
+	return &MCTSBot{
+		thinkTime: thinkTime,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		Workers:   runtime.NumCPU(),
+	}
+}
+
+// NewMCTSBotWithIterations creates an MCTS bot that plays a fixed number of playouts per
+// move, so its strength doesn't depend on the machine. The seed makes its play repeatable
+// when it searches with a single worker.
+func NewMCTSBotWithIterations(iterations int, seed int64) *MCTSBot {
+	return &MCTSBot{
+		iterations: iterations,
+		rng:        rand.New(rand.NewSource(seed)),
+		Workers:    runtime.NumCPU(),
+	}
+}
+
+// Name returns the bot's name
+func (b *MCTSBot) Name() string {
+	if b.iterations > 0 {
+		return fmt.Sprintf("mcts:%d", b.iterations)
+	}
+	return fmt.Sprintf("mcts:%s", b.thinkTime)
+}
+
+// MoveStats is what the search learned about one move from the current position
+type MoveStats struct {
+	Move   game.Move
+	Visits int
+	Wins   float64 // Playouts won after the move, counting draws as half
+}
+
+// WinRate returns the share of playouts after the move that were won
+func (m MoveStats) WinRate() float64 {
+	if m.Visits == 0 {
+		return 0
+	}
+	return m.Wins / float64(m.Visits)
+}
+
+// SearchStats summarizes one search, with the moves from the most visited down
+type SearchStats struct {
+	Player     game.PlayerColor
+	Iterations int
+	Workers    int
+	Elapsed    time.Duration
+	Moves      []MoveStats
+}
+
+// String formats the statistics for display, listing the top few moves
+func (s SearchStats) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🤖 %s searched %d playouts in %s with %d worker(s)",
+		s.Player, s.Iterations, s.Elapsed.Round(time.Millisecond), s.Workers)
+	for i, move := range s.Moves {
+		if i == 5 {
+			break
+		}
+		fmt.Fprintf(&sb, "\n   %-4s %7d visits  %5.1f%% wins", move.Move, move.Visits, 100*move.WinRate())
+	}
+	return sb.String()
+}
+
+// ChooseMove searches from a copy of the current position and plays the most visited move
+func (b *MCTSBot) ChooseMove(g game.Game) (game.Move, error) {
+	start := time.Now()
+	root := g.GetState()
+	player := root.CurrentPlayer
+	if len(game.LegalMoves(root)) == 0 {
+		return game.Move{}, fmt.Errorf("%s has no legal moves", player)
+	}
+
+	workers := max(b.Workers, 1)
+	deadline := start.Add(b.thinkTime)
+
+	// Root parallelism: independent trees, merged at the root
+	results := make([][]MoveStats, workers)
+	iterations := make([]int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		budget := 0
+		if b.iterations > 0 {
+			budget = b.iterations / workers
+			if w < b.iterations%workers {
+				budget++
+			}
+		}
+
+		worker := &mctsWorker{root: root, player: player, rng: rand.New(rand.NewSource(b.rng.Int63()))}
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			results[w], iterations[w] = worker.search(budget, deadline)
+		}(w)
+	}
+	wg.Wait()
+
+	stats := SearchStats{Player: player, Workers: workers}
+	merged := make(map[game.Move]*MoveStats)
+	for w := range results {
+		stats.Iterations += iterations[w]
+		for _, move := range results[w] {
+			total, ok := merged[move.Move]
+			if !ok {
+				total = &MoveStats{Move: move.Move}
+				merged[move.Move] = total
+			}
+			total.Visits += move.Visits
+			total.Wins += move.Wins
+		}
+	}
+	for _, move := range merged {
+		stats.Moves = append(stats.Moves, *move)
+	}
+	sort.Slice(stats.Moves, func(i, j int) bool {
+		a, b := stats.Moves[i], stats.Moves[j]
+		if a.Visits != b.Visits {
+			return a.Visits > b.Visits
+		}
+		return a.Wins > b.Wins
+	})
+	stats.Elapsed = time.Since(start)
+
+	if b.OnSearch != nil {
+		b.OnSearch(stats)
+	}
+	if len(stats.Moves) == 0 {
+		return game.Move{}, fmt.Errorf("search found no moves")
+	}
+	return stats.Moves[0].Move, nil
+}
+
+// mctsNode is a position in a worker's search tree
+type mctsNode struct {
+	move     game.Move        // Move that led here from the parent
+	mover    game.PlayerColor // Player who made that move
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []game.Move // Legal moves not expanded into children yet
+	visits   int
+	wins     float64 // Playouts through this node won by mover
+}
+
+// newNode creates a tree node for the position after mover's move
+func newNode(parent *mctsNode, move game.Move, mover game.PlayerColor, state game.GameState) *mctsNode {
+	return &mctsNode{move: move, mover: mover, parent: parent, untried: game.LegalMoves(state)}
+}
+
+// selectChild picks the child with the best upper confidence bound
+func (n *mctsNode) selectChild() *mctsNode {
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	logVisits := math.Log(float64(n.visits))
+	for _, child := range n.children {
+		score := child.wins/float64(child.visits) + explorationConst*math.Sqrt(logVisits/float64(child.visits))
+		if score > bestScore {
+			best, bestScore = child, score
+		}
+	}
+	return best
+}
+
+// mctsWorker grows one search tree. Workers only read the root state, so they can share it.
+type mctsWorker struct {
+	root   game.GameState
+	player game.PlayerColor
+	rng    *rand.Rand
+}
+
+// search runs iterations until the budget is spent, or until the deadline when the budget
+// is 0, and returns the statistics of the root's children and the iterations run
+func (w *mctsWorker) search(budget int, deadline time.Time) ([]MoveStats, int) {
+	tree := newNode(nil, game.Move{}, w.player.Opponent(), w.root)
+
+	done := 0
+	for budget > 0 && done < budget || budget == 0 && time.Now().Before(deadline) {
+		w.iterate(tree)
+		done++
+	}
+
+	stats := make([]MoveStats, len(tree.children))
+	for i, child := range tree.children {
+		stats[i] = MoveStats{Move: child.move, Visits: child.visits, Wins: child.wins}
+	}
+	return stats, done
+}
+
+// iterate runs one round of selection, expansion, playout and backpropagation
+func (w *mctsWorker) iterate(tree *mctsNode) {
+	state := w.root
+	node := tree
+
+	// Follow the most promising moves down to a node with moves left to try
+	for len(node.untried) == 0 && len(node.children) > 0 {
+		node = node.selectChild()
+		state, _ = game.Apply(state, node.move)
+	}
+
+	// Add one of its untried moves to the tree
+	if len(node.untried) > 0 {
+		i := w.rng.Intn(len(node.untried))
+		move := node.untried[i]
+		node.untried[i] = node.untried[len(node.untried)-1]
+		node.untried = node.untried[:len(node.untried)-1]
+
+		state, _ = game.Apply(state, move)
+		child := newNode(node, move, node.mover.Opponent(), state)
+		node.children = append(node.children, child)
+		node = child
+	}
+
+	winner := w.playout(state)
+	for ; node != nil; node = node.parent {
+		node.visits++
+		switch winner {
+		case node.mover:
+			node.wins++
+		case game.NoPlayer:
+			node.wins += 0.5
+		}
+	}
+}
+
+// playout plays random moves from the position and returns the winner, judging games that
+// run too long by Evaluate. NoPlayer means neither side is ahead.
+func (w *mctsWorker) playout(state game.GameState) game.PlayerColor {
+	for moves := 0; moves < maxPlayoutMoves && !state.GameOver; moves++ {
+		legal := game.LegalMoves(state)
+		if len(legal) == 0 {
+			break
+		}
+		state, _ = game.Apply(state, legal[w.rng.Intn(len(legal))])
+	}
+
+	if state.GameOver {
+		return state.Winner
+	}
+	switch score := Evaluate(state, game.Red); {
+	case score > 0:
+		return game.Red
+	case score < 0:
+		return game.Blue
+	default:
+		return game.NoPlayer
+	}
+}
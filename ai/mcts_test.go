@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"micemen/game"
+)
+
+func TestMCTSFindsWinningMove(t *testing.T) {
+	g := loadGame(t, winInOneMap)
+	bot := NewMCTSBotWithIterations(200, 1)
+	bot.Workers = 1
+	
+	move, err := bot.ChooseMove(g)
+	if err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	if want := (game.Move{Column: 5, Direction: game.DirectionDown}); move != want {
+		t.Errorf("MCTS should play the winning move %v, got %v", want, move)
+	}
+	if g.GetState().Escaped[game.Red] != 1 {
+		t.Errorf("Searching shouldn't change the live game")
+	}
+}
+
+func TestMCTSReportsStats(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 3)
+	bot := NewMCTSBotWithIterations(101, 1)
+	bot.Workers = 2
+	
+	var stats SearchStats
+	bot.OnSearch = func(s SearchStats) { stats = s }
+	move, err := bot.ChooseMove(g)
+	if err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	
+	if stats.Iterations != 101 || stats.Workers != 2 || stats.Player != game.Red {
+		t.Errorf("Stats should count 101 playouts by 2 workers for Red, got %+v", stats)
+	}
+	if len(stats.Moves) == 0 || stats.Moves[0].Move != move {
+		t.Fatalf("Stats should list the chosen move first, got %+v", stats.Moves)
+	}
+	
+	visits := 0
+	for i, m := range stats.Moves {
+		visits += m.Visits
+		if i > 0 && m.Visits > stats.Moves[i-1].Visits {
+			t.Errorf("Moves should be listed from the most visited down")
+		}
+		if rate := m.WinRate(); rate < 0 || rate > 1 {
+			t.Errorf("Win rate of %v should be between 0 and 1, got %f", m.Move, rate)
+		}
+	}
+	if visits != 101 {
+		t.Errorf("Root visits should add up to the playouts, got %d", visits)
+	}
+}
+
+func TestMCTSThinkTime(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 3)
+	bot := NewMCTSBot(50 * time.Millisecond)
+	
+	start := time.Now()
+	if _, err := bot.ChooseMove(g); err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Bot should stop thinking after about 50ms, took %s", elapsed)
+	}
+}
+
+func TestNewMCTSBot(t *testing.T) {
+	tests := map[string]string{
+		"mcts":       "mcts:1s",
+		"mcts:250ms": "mcts:250ms",
+		"mcts:5000":  "mcts:5000",
+	}
+	for spec, name := range tests {
+		bot, err := New(spec)
+		if err != nil || bot.Name() != name {
+			t.Errorf("New(%q) should create %s, got %v, %v", spec, name, bot, err)
+		}
+	}
+	
+	for _, spec := range []string{"mcts:0", "mcts:-1s", "mcts:fast"} {
+		if _, err := New(spec); err == nil {
+			t.Errorf("New(%q) should fail", spec)
+		}
+	}
+}
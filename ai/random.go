@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"micemen/game"
+)
+
+// RandomBot plays a uniformly random legal move. It's a baseline for stronger bots.
+type RandomBot struct {
+	rng *rand.Rand
+}
+
+// NewRandomBot creates a random bot seeded from the clock
+func NewRandomBot() *RandomBot {
+	return NewRandomBotWithSeed(time.Now().UnixNano())
+}
+
+// NewRandomBotWithSeed creates a random bot whose moves are reproducible from the seed
+func NewRandomBotWithSeed(seed int64) *RandomBot {
+	return &RandomBot{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Name returns the bot's name
+func (b *RandomBot) Name() string {
+	return "random"
+}
+
+// ChooseMove picks one of the current player's legal moves at random
+func (b *RandomBot) ChooseMove(g game.Game) (game.Move, error) {
+	state := g.GetState()
+	moves := game.LegalMoves(state)
+	if len(moves) == 0 {
+		return game.Move{}, fmt.Errorf("%s has no legal moves", state.CurrentPlayer)
+	}
+	return moves[b.rng.Intn(len(moves))], nil
+}
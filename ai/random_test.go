@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"testing"
+
+	"micemen/game"
+)
+
+func TestRandomBotPlaysLegalMoves(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 1)
+	bot := NewRandomBotWithSeed(1)
+	
+	// Two random bots should always finish a game with legal moves
+	for turn := 0; !g.IsGameOver(); turn++ {
+		if turn > 10000 {
+			t.Fatal("Game between random bots should finish")
+		}
+		before := g.GetState()
+		move, err := bot.ChooseMove(g)
+		if err != nil {
+			t.Fatalf("ChooseMove failed on turn %d: %v", turn, err)
+		}
+		if after := g.GetState(); after.CurrentPlayer != before.CurrentPlayer || len(after.Mice) != len(before.Mice) {
+			t.Fatalf("ChooseMove shouldn't change the game")
+		}
+		if err := g.ApplyMove(move); err != nil {
+			t.Fatalf("Random bot chose an illegal move %v on turn %d: %v", move, turn, err)
+		}
+	}
+	
+	if state := g.GetState(); state.Winner == game.NoPlayer {
+		t.Errorf("Finished game should have a winner, got %+v", state.Result)
+	}
+}
+
+func TestRandomBotIsReproducible(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 1)
+	a, b := NewRandomBotWithSeed(7), NewRandomBotWithSeed(7)
+	for i := 0; i < 20; i++ {
+		moveA, _ := a.ChooseMove(g)
+		moveB, _ := b.ChooseMove(g)
+		if moveA != moveB {
+			t.Fatalf("Bots with the same seed should choose the same moves, got %v and %v", moveA, moveB)
+		}
+	}
+}
+
+func TestNewBot(t *testing.T) {
+	bot, err := New("random")
+	if err != nil || bot.Name() != "random" {
+		t.Errorf("New(random) should create the random bot, got %v, %v", bot, err)
+	}
+	
+	for _, spec := range []string{"", "human", "random:3", "perfect"} {
+		if _, err := New(spec); err == nil {
+			t.Errorf("New(%q) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"sync"
+
+	"micemen/game"
+)
+
+// Bound says how a stored score relates to the true score of a position, since alpha-beta
+// only finds exact scores for moves inside its window
+type Bound int
+
+const (
+	BoundExact Bound = iota // Score is the position's score
+	BoundLower              // Score is at least this (the search cut off above beta)
+	BoundUpper              // Score is at most this (no move beat alpha)
+)
+
+// TTEntry is what a search learned about a position
+type TTEntry struct {
+	Hash  uint64 // Zobrist hash of the position, to tell apart positions sharing a slot
+	Depth int    // How many moves deep the position was searched
+	Score int
+	Bound Bound
+	Move  game.Move // Best move found, worth trying first next time
+}
+
+// DefaultTableSize is the number of entries in a transposition table made by a bot
+const DefaultTableSize = 1 << 18
+
+// ttShards is the number of locks a table's slots are spread over, so searches running in
+// parallel rarely wait for each other
+const ttShards = 64
+
+// TranspositionTable remembers searched positions by hash, so positions reached again by
+// a different move order aren't searched twice. It has a fixed number of slots, with
+// deeper searches replacing shallower ones, and is safe to share between goroutines.
+type TranspositionTable struct {
+	slots []TTEntry
+	used  []bool
+	locks [ttShards]sync.Mutex
+}
+
+// NewTranspositionTable creates a table with the given number of slots
+func NewTranspositionTable(size int) *TranspositionTable {
+	size = max(size, 1)
+	return &TranspositionTable{
+		slots: make([]TTEntry, size),
+		used:  make([]bool, size),
+	}
+}
+
+// slot returns the index of a hash's slot
+func (t *TranspositionTable) slot(hash uint64) int {
+	return int(hash % uint64(len(t.slots)))
+}
+
+// Lookup returns the entry stored for a position, if there is one
+func (t *TranspositionTable) Lookup(hash uint64) (TTEntry, bool) {
+	i := t.slot(hash)
+	lock := &t.locks[i%ttShards]
+	lock.Lock()
+	defer lock.Unlock()
+
+	entry := t.slots[i]
+	if !t.used[i] || entry.Hash != hash {
+		return TTEntry{}, false
+	}
+	return entry, true
+}
+
+// Store records what a search learned about a position. It replaces what was in the slot
+// unless that was a deeper search of the same position.
+func (t *TranspositionTable) Store(entry TTEntry) {
+	i := t.slot(entry.Hash)
+	lock := &t.locks[i%ttShards]
+	lock.Lock()
+	defer lock.Unlock()
+
+	if old := t.slots[i]; t.used[i] && old.Hash == entry.Hash && old.Depth > entry.Depth {
+		return
+	}
+	t.slots[i] = entry
+	t.used[i] = true
+}
+
+// Clear empties the table
+func (t *TranspositionTable) Clear() {
+	for i := range t.locks {
+		t.locks[i].Lock()
+	}
+	defer func() {
+		for i := range t.locks {
+			t.locks[i].Unlock()
+		}
+	}()
+
+	clear(t.used)
+}
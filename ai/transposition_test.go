@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"sync"
+	"testing"
+
+	"micemen/game"
+)
+
+func TestTranspositionTableStoresAndLooksUp(t *testing.T) {
+	table := NewTranspositionTable(16)
+	entry := TTEntry{Hash: 0xabc, Depth: 3, Score: 42, Bound: BoundLower, Move: game.Move{Column: 2, Direction: game.DirectionUp}}
+	table.Store(entry)
+	
+	got, ok := table.Lookup(0xabc)
+	if !ok || got != entry {
+		t.Errorf("Lookup should return the stored entry %+v, got %+v (found %v)", entry, got, ok)
+	}
+	if _, ok := table.Lookup(0xabc + 16); ok {
+		t.Errorf("Lookup of a different hash in the same slot should miss")
+	}
+	if _, ok := table.Lookup(0); ok {
+		t.Errorf("Lookup of an empty slot should miss")
+	}
+}
+
+func TestTranspositionTableKeepsDeeperEntries(t *testing.T) {
+	table := NewTranspositionTable(16)
+	table.Store(TTEntry{Hash: 5, Depth: 4, Score: 10})
+	table.Store(TTEntry{Hash: 5, Depth: 2, Score: 20})
+	if got, _ := table.Lookup(5); got.Depth != 4 {
+		t.Errorf("A shallower search of the same position shouldn't replace a deeper one, got depth %d", got.Depth)
+	}
+	
+	// Another position in the same slot always replaces it
+	table.Store(TTEntry{Hash: 21, Depth: 1, Score: 30})
+	if got, ok := table.Lookup(21); !ok || got.Score != 30 {
+		t.Errorf("A different position should replace the slot's entry")
+	}
+	if _, ok := table.Lookup(5); ok {
+		t.Errorf("The replaced position should no longer be found")
+	}
+}
+
+func TestTranspositionTableClear(t *testing.T) {
+	table := NewTranspositionTable(16)
+	for hash := uint64(0); hash < 16; hash++ {
+		table.Store(TTEntry{Hash: hash, Depth: 1})
+	}
+	table.Clear()
+	for hash := uint64(0); hash < 16; hash++ {
+		if _, ok := table.Lookup(hash); ok {
+			t.Errorf("Lookup of %d should miss after Clear", hash)
+		}
+	}
+}
+
+func TestTranspositionTableIsSafeToShare(t *testing.T) {
+	table := NewTranspositionTable(256)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				hash := uint64(w*1000 + i)
+				table.Store(TTEntry{Hash: hash, Depth: i % 5, Score: i})
+				if entry, ok := table.Lookup(hash); ok && entry.Hash != hash {
+					t.Errorf("Lookup of %d returned the entry for %d", hash, entry.Hash)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestAlphaBetaSharedTable(t *testing.T) {
+	table := NewTranspositionTable(DefaultTableSize)
+	first, second := NewAlphaBetaBot(3), NewAlphaBetaBot(3)
+	first.Table, second.Table = table, table
+	
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 8)
+	want, err := NewAlphaBetaBot(3).ChooseMove(g)
+	if err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	
+	// A bot searching with what another bot left in the table should pick the same move
+	for _, bot := range []*AlphaBetaBot{first, second} {
+		move, err := bot.ChooseMove(g)
+		if err != nil {
+			t.Fatalf("ChooseMove failed: %v", err)
+		}
+		if move != want {
+			t.Errorf("Search with a shared table should play %v like a fresh bot, got %v", want, move)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"micemen/ai"
+	"micemen/game"
+	"micemen/render"
+)
+
+// Analysis overlay settings
+const (
+	analysisDepth = ai.DefaultSearchDepth
+	analysisPoll  = 100 * time.Millisecond // How often a waiting engine checks for scores and solved endgames
+)
+
+// analyzer scores every move of the position on screen in the background. Moving on to
+// another position cancels the search of the old one.
+type analyzer struct {
+	render *render.TerminalRenderer
+	bot    *ai.AlphaBetaBot
+
+	shown  uint64             // Hash of the position whose scores are on screen, or 0
+	cancel context.CancelFunc // Stops the search under way, or nil if none was started
+
+	mu      sync.Mutex
+	started uint64           // Hash of the position last sent off for scoring
+	latest  *render.Analysis // Scores of the started position once they're in
+}
+
+// newAnalyzer creates an analyzer that shows its scores through the renderer
+func newAnalyzer(r *render.TerminalRenderer) *analyzer {
+	return &analyzer{render: r, bot: ai.NewAlphaBetaBot(analysisDepth)}
+}
+
+// start scores the position in the background unless that's already under way, and
+// shows the overlay as working in the meantime
+func (a *analyzer) start(state game.GameState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil && a.started == state.Hash {
+		return
+	}
+	if a.cancel != nil {
+		a.cancel()
+	}
+	if state.GameOver {
+		a.cancel = nil
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.started, a.latest, a.shown = state.Hash, nil, 0
+	a.render.SetAnalysis(&render.Analysis{Hash: state.Hash, Depth: analysisDepth})
+
+	go func() {
+		scores, err := a.bot.ScoreMoves(ctx, state)
+		if err != nil {
+			return
+		}
+
+		// A search that finished just as it was cancelled mustn't replace newer scores
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if state.Hash == a.started {
+			a.latest = &render.Analysis{Hash: state.Hash, Depth: analysisDepth, Scores: scores}
+		}
+	}()
+}
+
+// stop cancels any search under way
+func (a *analyzer) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// pending reports whether the scores of the last position sent off aren't on screen yet
+func (a *analyzer) pending() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cancel != nil && a.shown != a.started
+}
+
+// collect puts the scores for the position on screen if they have come in, and reports
+// whether they did
+func (a *analyzer) collect(state game.GameState) bool {
+	a.mu.Lock()
+	latest := a.latest
+	a.mu.Unlock()
+	if latest == nil || latest.Hash != state.Hash || a.shown == state.Hash {
+		return false
+	}
+
+	a.shown = state.Hash
+	a.render.SetAnalysis(latest)
+	return true
+}
+
+// toggleAnalysis turns the analysis overlay on or off
+func (e *GameEngine) toggleAnalysis() {
+	termRender, ok := e.render.(*render.TerminalRenderer)
+	if !ok {
+		return
+	}
+
+	if e.analysis != nil {
+		e.analysis.stop()
+		e.analysis = nil
+		termRender.SetAnalysis(nil)
+	} else {
+		e.analysis = newAnalyzer(termRender)
+	}
+	e.renderState()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"micemen/game"
+	"micemen/render"
+)
+
+// waitForScores collects the analyzer's scores for the position, failing after a while
+func waitForScores(t *testing.T, a *analyzer, state game.GameState) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !a.collect(state) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Scores for the position never came in")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAnalyzerKeepsOnlyTheLatestPosition(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 6)
+	a := newAnalyzer(render.NewTerminalRenderer(g))
+	first := g.GetState()
+	
+	// Moving on before the first search is done supersedes it
+	a.start(first)
+	if err := g.ApplyMove(game.LegalMoves(first)[0]); err != nil {
+		t.Fatalf("ApplyMove failed: %v", err)
+	}
+	second := g.GetState()
+	a.start(second)
+	
+	waitForScores(t, a, second)
+	if a.pending() {
+		t.Errorf("Nothing should be pending once the scores are on screen")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if a.collect(first) {
+		t.Errorf("Scores for a superseded position shouldn't come in")
+	}
+	
+	// Going back to the first position scores it again
+	a.start(first)
+	if !a.pending() {
+		t.Errorf("A new position should be pending until its scores are in")
+	}
+	waitForScores(t, a, first)
+	a.stop()
+}
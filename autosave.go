@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"micemen/game"
+)
+
+// savedGame is the autosave file: the starting board and the moves played on it, so a
+// resumed game keeps its undo history
+type savedGame struct {
+	Initial game.GameState `json:"initial"`
+	Moves   []game.Move    `json:"moves"`
+}
+
+// autosavePath returns where the in-progress game is saved, under the user's config dir
+func autosavePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "micemen", "autosave.json"), nil
+}
+
+// saveGame writes the game to the autosave file. The file is replaced in one step so a
+// crash mid-write never leaves a broken save behind.
+func saveGame(path string, g game.Game) error {
+	data, err := json.MarshalIndent(savedGame{Initial: g.InitialState(), Moves: g.Moves()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadSavedGame rebuilds the game in the autosave file by replaying its moves
+func loadSavedGame(path string) (*game.MicemenGame, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved savedGame
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	g := game.NewGameFromState(saved.Initial)
+	for i, move := range saved.Moves {
+		if err := g.ApplyMove(move); err != nil {
+			return nil, fmt.Errorf("%s: move %d (%s): %w", path, i+1, move, err)
+		}
+	}
+	return g, nil
+}
+
+// askResume asks whether to pick up the saved game, defaulting to yes
+func askResume(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	fmt.Printf("An unfinished game was saved on %s. Resume it? [Y/n] ", info.ModTime().Format("Jan 2 15:04"))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"micemen/game"
+)
+
+func TestSaveAndLoadGame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "micemen", "autosave.json")
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 7)
+	for i := 0; i < 3; i++ {
+		if err := g.ApplyMove(game.LegalMoves(g.GetState())[i]); err != nil {
+			t.Fatalf("ApplyMove failed: %v", err)
+		}
+	}
+	
+	if err := saveGame(path, g); err != nil {
+		t.Fatalf("saveGame failed: %v", err)
+	}
+	loaded, err := loadSavedGame(path)
+	if err != nil {
+		t.Fatalf("loadSavedGame failed: %v", err)
+	}
+	
+	want, got := g.GetState(), loaded.GetState()
+	if got.Hash != want.Hash || got.CurrentPlayer != want.CurrentPlayer {
+		t.Errorf("Loaded game should be at the saved position")
+	}
+	if got.LastShiftColumn != want.LastShiftColumn || got.LastShiftDirection != want.LastShiftDirection {
+		t.Errorf("Loaded game should keep the lock on column %d %v, got column %d %v",
+			want.LastShiftColumn, want.LastShiftDirection, got.LastShiftColumn, got.LastShiftDirection)
+	}
+	if moves := loaded.Moves(); len(moves) != 3 || moves[2] != g.Moves()[2] {
+		t.Errorf("Loaded game should have the moves %v, got %v", g.Moves(), moves)
+	}
+	if undo, _ := loaded.HistoryDepth(); undo != 3 {
+		t.Errorf("Loaded game should be able to take back 3 moves, got %d", undo)
+	}
+}
+
+func TestLoadSavedGameErrors(t *testing.T) {
+	dir := t.TempDir()
+	
+	missing := filepath.Join(dir, "missing.json")
+	if _, err := loadSavedGame(missing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("A missing save should give os.ErrNotExist, got %v", err)
+	}
+	if askResume(missing) {
+		t.Errorf("There should be nothing to resume without a save")
+	}
+	
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"corrupt", `{"initial": {"version": 1, "grid": [`, "unexpected end of JSON input"},
+		{"newer", `{"initial": {"version": 99}, "moves": []}`, "newer than this build supports"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".json")
+		if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadSavedGame(path)
+		if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("A %s save should fail naming the file and %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestResumeRejectsBoardFlags(t *testing.T) {
+	if err := runPlay([]string{"-resume", "-seed", "1"}); !errors.Is(err, errUsage) {
+		t.Errorf("Resuming with a board flag should be a usage error, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"micemen/book"
+)
+
+// runBook handles "micemen book build|inspect|merge": opening book maintenance
+func runBook(args []string) error {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: micemen book build [flags] <file>")
+		fmt.Fprintln(os.Stderr, "       micemen book inspect [flags] <file>")
+		fmt.Fprintln(os.Stderr, "       micemen book merge <output> <book>...")
+	}
+	if len(args) == 0 {
+		usage()
+		return errUsage
+	}
+
+	switch args[0] {
+	case "build":
+		return runBookBuild(args[1:])
+	case "inspect":
+		return runBookInspect(args[1:])
+	case "merge":
+		return runBookMerge(args[1:])
+	default:
+		usage()
+		return errUsage
+	}
+}
+
+// runBookBuild plays self-play games and saves the book of their openings
+func runBookBuild(args []string) error {
+	fs := flag.NewFlagSet("book build", flag.ExitOnError)
+	opts := addGameFlags(fs)
+	boards := fs.Int("boards", 1, "number of boards to play on, with seeds counting up from -seed")
+	games := fs.Int("games", 100, "self-play games per board")
+	bot := fs.String("bot", "alphabeta:2", "bot playing both sides")
+	plies := fs.Int("plies", book.DefaultPlies, "opening moves of each game to add to the book")
+	explore := fs.Float64("explore", book.DefaultExplore, "chance of a random opening move instead of the bot's")
+	workers := fs.Int("workers", runtime.NumCPU(), "games to play in parallel")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: micemen book build [flags] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || *boards < 1 || *games < 1 || *plies < 1 || *explore < 0 || *explore > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	path := fs.Arg(0)
+
+	cfg, gen, err := opts.board()
+	if err != nil {
+		return err
+	}
+	seed := opts.boardSeed()
+
+	b, err := book.Build(book.BuildOptions{
+		Config:    cfg,
+		Generator: gen,
+		Seed:      seed,
+		Boards:    *boards,
+		Games:     *games,
+		Bot:       *bot,
+		Plies:     *plies,
+		Explore:   *explore,
+		Workers:   *workers,
+		Progress: func(done, total int) {
+			fmt.Printf("\rPlayed %d of %d games", done, total)
+		},
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	if err := book.Save(path, b); err != nil {
+		return fmt.Errorf("saving book: %w", err)
+	}
+	positions, moves := b.Size()
+	fmt.Printf("Book of %d positions and %d moves saved to %s (board seeds %d to %d)\n",
+		positions, moves, path, seed, seed+int64(*boards)-1)
+	return nil
+}
+
+// runBookInspect summarizes a book, listing its moves for a board when one is given
+func runBookInspect(args []string) error {
+	fs := flag.NewFlagSet("book inspect", flag.ExitOnError)
+	opts := addGameFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: micemen book inspect [flags] <file>")
+		fmt.Fprintln(fs.Output(), "Give -seed or -map to list the book moves for that board's first position.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	path := fs.Arg(0)
+
+	b, err := book.Load(path)
+	if err != nil {
+		return err
+	}
+	positions, moves := b.Size()
+	fmt.Printf("%s: %d positions, %d moves\n", path, positions, moves)
+
+	if !opts.seedGiven() && *opts.mapPath == "" {
+		return nil
+	}
+	g, err := opts.newGame()
+	if err != nil {
+		return err
+	}
+	state := g.GetState()
+
+	stats := b.Moves(state.Hash)
+	if len(stats) == 0 {
+		fmt.Println("The book has no moves for this board.")
+		return nil
+	}
+	best, hasBest := b.Best(state.Hash, book.DefaultMinGames)
+	fmt.Printf("\n%s to move (position %016x):\n", state.CurrentPlayer, state.Hash)
+	for _, move := range stats {
+		marker := " "
+		if hasBest && move.Move == best {
+			marker = "*"
+		}
+		fmt.Printf(" %s %-4s %6d games  %5.1f%% wins\n", marker, move.Move, move.Games, 100*move.WinRate())
+	}
+	return nil
+}
+
+// runBookMerge combines books into one, adding up the games of moves they share
+func runBookMerge(args []string) error {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: micemen book merge <output> <book>...")
+		return errUsage
+	}
+
+	merged := book.New()
+	for _, path := range args[1:] {
+		b, err := book.Load(path)
+		if err != nil {
+			return err
+		}
+		merged.Merge(b)
+	}
+
+	if err := book.Save(args[0], merged); err != nil {
+		return fmt.Errorf("saving book: %w", err)
+	}
+	positions, moves := merged.Size()
+	fmt.Printf("Merged %d books into %s: %d positions, %d moves\n", len(args)-1, args[0], positions, moves)
+	return nil
+}
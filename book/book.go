@@ -0,0 +1,228 @@
+// Package book implements opening books: win rates of the early moves of bot games,
+// looked up by position so bots can play their first moves without thinking
+package book
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"micemen/game"
+)
+
+// A book file lists one move per line, under the hash of the position it was played in,
+// with the number of games it was played in and the games won by the player who made it
+// (draws count as half):
+//
+//	micemen-book 1
+//	# position       move  games  wins
+//	07a4c1d2e3f4a5b6 7U    120    64.5
+//	07a4c1d2e3f4a5b6 12D   35     11
+//
+// Lines starting with '#' are comments. Positions are identified by their Zobrist hash,
+// which covers the walls, the mice on the board, the last shift and the side to move,
+// so a book only helps on boards it was built on, such as a seed or map played often.
+
+// bookMagic is the first line of every book file
+const bookMagic = "micemen-book 1"
+
+// DefaultMinGames is how many games a move needs before bots trust its win rate
+const DefaultMinGames = 5
+
+// MoveStats is the record of one move from a book position
+type MoveStats struct {
+	Move  game.Move
+	Games int
+	Wins  float64 // Games won by the player who made the move, counting draws as half
+}
+
+// WinRate returns the share of games won after the move
+func (m MoveStats) WinRate() float64 {
+	if m.Games == 0 {
+		return 0
+	}
+	return m.Wins / float64(m.Games)
+}
+
+// Book holds move statistics by position hash
+type Book struct {
+	positions map[uint64][]MoveStats
+}
+
+// New creates an empty book
+func New() *Book {
+	return &Book{positions: make(map[uint64][]MoveStats)}
+}
+
+// Add records a game in which the move was played from the position. Score is the result
+// for the player who made the move: 1 for a win, 0.5 for a draw and 0 for a loss.
+func (b *Book) Add(hash uint64, move game.Move, score float64) {
+	b.add(hash, MoveStats{Move: move, Games: 1, Wins: score})
+}
+
+// add adds move statistics to the position's entry for the move
+func (b *Book) add(hash uint64, stats MoveStats) {
+	moves := b.positions[hash]
+	for i := range moves {
+		if moves[i].Move == stats.Move {
+			moves[i].Games += stats.Games
+			moves[i].Wins += stats.Wins
+			return
+		}
+	}
+	b.positions[hash] = append(moves, stats)
+}
+
+// Merge adds every move in another book to this one
+func (b *Book) Merge(other *Book) {
+	for hash, moves := range other.positions {
+		for _, stats := range moves {
+			b.add(hash, stats)
+		}
+	}
+}
+
+// Moves returns what the book knows about the position, most played move first
+func (b *Book) Moves(hash uint64) []MoveStats {
+	moves := slices.Clone(b.positions[hash])
+	slices.SortStableFunc(moves, func(x, y MoveStats) int {
+		if x.Games != y.Games {
+			return y.Games - x.Games
+		}
+		return compareMoves(x.Move, y.Move)
+	})
+	return moves
+}
+
+// Best returns the move with the best win rate from the position, among moves played in
+// at least minGames games
+func (b *Book) Best(hash uint64, minGames int) (game.Move, bool) {
+	var best MoveStats
+	found := false
+	for _, stats := range b.Moves(hash) {
+		if stats.Games < minGames {
+			continue
+		}
+		if !found || stats.WinRate() > best.WinRate() {
+			best, found = stats, true
+		}
+	}
+	return best.Move, found
+}
+
+// Size returns the number of positions and moves in the book
+func (b *Book) Size() (positions, moves int) {
+	for _, stats := range b.positions {
+		moves += len(stats)
+	}
+	return len(b.positions), moves
+}
+
+// compareMoves orders moves by column, then up before down
+func compareMoves(x, y game.Move) int {
+	if x.Column != y.Column {
+		return x.Column - y.Column
+	}
+	return int(x.Direction) - int(y.Direction)
+}
+
+// Load reads a book file from disk
+func Load(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Save writes a book file to disk
+func Save(path string, b *Book) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := Write(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes a book, with positions in hash order so files diff cleanly
+func Write(w io.Writer, b *Book) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, bookMagic)
+	fmt.Fprintln(bw, "# position       move  games  wins")
+
+	hashes := make([]uint64, 0, len(b.positions))
+	for hash := range b.positions {
+		hashes = append(hashes, hash)
+	}
+	slices.Sort(hashes)
+
+	for _, hash := range hashes {
+		moves := slices.Clone(b.positions[hash])
+		slices.SortFunc(moves, func(x, y MoveStats) int { return compareMoves(x.Move, y.Move) })
+		for _, stats := range moves {
+			fmt.Fprintf(bw, "%016x %-5s %-6d %s\n",
+				hash, stats.Move, stats.Games, strconv.FormatFloat(stats.Wins, 'f', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
+
+// Read reads a book
+func Read(r io.Reader) (*Book, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != bookMagic {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("not a book file: the first line should be %q", bookMagic)
+	}
+
+	b := New()
+	for line := 2; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected position, move, games and wins", line)
+		}
+		hash, err := strconv.ParseUint(fields[0], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid position hash %q", line, fields[0])
+		}
+		move, err := game.ParseMove(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		games, err := strconv.Atoi(fields[2])
+		if err != nil || games < 1 {
+			return nil, fmt.Errorf("line %d: invalid game count %q", line, fields[2])
+		}
+		wins, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil || wins < 0 || wins > float64(games) {
+			return nil, fmt.Errorf("line %d: invalid win count %q", line, fields[3])
+		}
+		b.add(hash, MoveStats{Move: move, Games: games, Wins: wins})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,155 @@
+package book
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"micemen/ai"
+	"micemen/game"
+)
+
+var (
+	up3   = game.Move{Column: 2, Direction: game.DirectionUp}
+	down3 = game.Move{Column: 2, Direction: game.DirectionDown}
+)
+
+func TestBookRoundTrip(t *testing.T) {
+	b := New()
+	b.Add(0xfeed, up3, 1)
+	b.Add(0xfeed, up3, 0.5)
+	b.Add(0xfeed, down3, 0)
+	b.Add(0x1, down3, 1)
+	
+	var buf bytes.Buffer
+	if err := Write(&buf, b); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	loaded, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	
+	if positions, moves := loaded.Size(); positions != 2 || moves != 3 {
+		t.Errorf("Loaded book should have 2 positions and 3 moves, got %d and %d", positions, moves)
+	}
+	got := loaded.Moves(0xfeed)
+	want := []MoveStats{{Move: up3, Games: 2, Wins: 1.5}, {Move: down3, Games: 1, Wins: 0}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Loaded moves should be %+v, got %+v", want, got)
+	}
+}
+
+func TestReadRejectsBadBooks(t *testing.T) {
+	cases := map[string]string{
+		"missing header": "0000000000000001 3U 1 1\n",
+		"bad hash":       "micemen-book 1\nxyz 3U 1 1\n",
+		"bad move":       "micemen-book 1\n0000000000000001 3X 1 1\n",
+		"too many wins":  "micemen-book 1\n0000000000000001 3U 1 2\n",
+		"missing field":  "micemen-book 1\n0000000000000001 3U 1\n",
+	}
+	for name, text := range cases {
+		if _, err := Read(strings.NewReader(text)); err == nil {
+			t.Errorf("Read should reject a book with a %s", name)
+		}
+	}
+}
+
+func TestMergeAddsUpGames(t *testing.T) {
+	a, b := New(), New()
+	a.Add(7, up3, 1)
+	b.Add(7, up3, 0)
+	b.Add(7, down3, 1)
+	b.Add(8, up3, 1)
+	a.Merge(b)
+	
+	if positions, moves := a.Size(); positions != 2 || moves != 3 {
+		t.Errorf("Merged book should have 2 positions and 3 moves, got %d and %d", positions, moves)
+	}
+	if got := a.Moves(7)[0]; got.Move != up3 || got.Games != 2 || got.Wins != 1 {
+		t.Errorf("Merging should add up the games of a shared move, got %+v", got)
+	}
+}
+
+func TestBestNeedsEnoughGames(t *testing.T) {
+	b := New()
+	for i := 0; i < 10; i++ {
+		b.Add(1, up3, float64(i%2))
+	}
+	b.Add(1, down3, 1)
+	
+	if move, ok := b.Best(1, 5); !ok || move != up3 {
+		t.Errorf("Best should skip moves with too few games and pick %v, got %v", up3, move)
+	}
+	if move, _ := b.Best(1, 1); move != down3 {
+		t.Errorf("Best should pick the move with the best win rate %v, got %v", down3, move)
+	}
+	if _, ok := b.Best(2, 1); ok {
+		t.Errorf("Best should find nothing for a position not in the book")
+	}
+}
+
+func TestBotPlaysFromBook(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 4)
+	state := g.GetState()
+	legal := game.LegalMoves(state)
+	want := legal[len(legal)-1]
+	
+	b := New()
+	for i := 0; i < DefaultMinGames; i++ {
+		b.Add(state.Hash, want, 1)
+	}
+	bot := NewBot(b, ai.NewRandomBotWithSeed(1))
+	if move, err := bot.ChooseMove(g); err != nil || move != want {
+		t.Errorf("Bot should play the book move %v, got %v (%v)", want, move, err)
+	}
+	
+	// Out of the book the fallback bot chooses
+	if err := g.ApplyMove(want); err != nil {
+		t.Fatalf("ApplyMove failed: %v", err)
+	}
+	move, err := bot.ChooseMove(g)
+	if err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	if err := g.ApplyMove(move); err != nil {
+		t.Errorf("Fallback move %v should be legal: %v", move, err)
+	}
+}
+
+func TestBuildRecordsOpenings(t *testing.T) {
+	cfg := game.NewConfig(11, 7, 0)
+	opts := BuildOptions{
+		Config:    cfg,
+		Generator: game.UniformGenerator{},
+		Seed:      21,
+		Boards:    2,
+		Games:     4,
+		Bot:       "random",
+		Plies:     3,
+		Workers:   2,
+	}
+	b, err := Build(opts)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	
+	// Every game adds its first move from the starting position of its board
+	for seed := int64(21); seed <= 22; seed++ {
+		start := game.NewGameWithSeed(cfg, game.UniformGenerator{}, seed).GetState()
+		games := 0
+		for _, move := range b.Moves(start.Hash) {
+			games += move.Games
+		}
+		if games != opts.Games {
+			t.Errorf("Board %d should have its %d games in the book from the start, got %d", seed, opts.Games, games)
+		}
+	}
+	if _, moves := b.Size(); moves > opts.Boards*opts.Games*opts.Plies {
+		t.Errorf("Book should only hold the first %d moves of each game, got %d moves", opts.Plies, moves)
+	}
+	
+	if _, err := Build(BuildOptions{Config: cfg, Generator: game.UniformGenerator{}, Boards: 1, Games: 1, Bot: "nobody"}); err == nil {
+		t.Errorf("Build should reject an unknown bot")
+	}
+}
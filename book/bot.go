@@ -0,0 +1,36 @@
+package book
+
+import (
+	"slices"
+
+	"micemen/ai"
+	"micemen/game"
+)
+
+// Bot plays from an opening book while the position is in it, and lets another bot
+// choose once the game leaves the book
+type Bot struct {
+	Book     *Book
+	Fallback ai.Bot
+	MinGames int // Games a move needs in the book before it is played
+}
+
+// NewBot wraps a bot so it plays book moves where it can
+func NewBot(b *Book, fallback ai.Bot) *Bot {
+	return &Bot{Book: b, Fallback: fallback, MinGames: DefaultMinGames}
+}
+
+// Name returns the fallback bot's name marked as using a book
+func (b *Bot) Name() string {
+	return b.Fallback.Name() + "+book"
+}
+
+// ChooseMove plays the best book move, or asks the fallback bot when there is none
+func (b *Bot) ChooseMove(g game.Game) (game.Move, error) {
+	state := g.GetState()
+	// A hash collision could suggest a move that isn't legal here
+	if move, ok := b.Book.Best(state.Hash, b.MinGames); ok && slices.Contains(game.LegalMoves(state), move) {
+		return move, nil
+	}
+	return b.Fallback.ChooseMove(g)
+}
@@ -0,0 +1,161 @@
+package book
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"micemen/ai"
+	"micemen/game"
+)
+
+// Self-play settings
+const (
+	DefaultPlies   = 8
+	DefaultExplore = 0.25
+	maxGameMoves   = 400 // Self-play games running longer than this are scored as draws
+)
+
+// BuildOptions describes the self-play games a book is built from
+type BuildOptions struct {
+	Config    game.Config
+	Generator game.BoardGenerator
+	Seed      int64 // Seed of the first board; each further board uses the next seed
+	Boards    int   // Number of boards to play on
+	Games     int   // Games per board
+
+	Bot     string  // Bot playing both sides, as given to ai.New
+	Plies   int     // How many opening moves of each game go in the book
+	Explore float64 // Chance of playing a random move instead of the bot's in the opening
+	Workers int     // Games played in parallel
+
+	// Progress, if set, is called after each game with the number finished so far
+	Progress func(done, total int)
+}
+
+// selfPlayGame is one game to play: the board seed and the seed of its random choices
+type selfPlayGame struct {
+	board int64
+	rng   int64
+}
+
+// Build plays the self-play games and returns the book of their opening moves. Random
+// moves in the opening make the games differ and give the book more than one move to
+// compare in each position.
+func Build(opts BuildOptions) (*Book, error) {
+	if opts.Boards < 1 || opts.Games < 1 {
+		return nil, fmt.Errorf("need at least one board and one game per board")
+	}
+	if opts.Plies < 1 {
+		opts.Plies = DefaultPlies
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	// Each worker plays with its own bot, since bots aren't safe for concurrent use
+	bots := make([]ai.Bot, workers)
+	for w := range bots {
+		bot, err := ai.New(opts.Bot)
+		if err != nil {
+			return nil, err
+		}
+		bots[w] = bot
+	}
+
+	total := opts.Boards * opts.Games
+	games := make(chan selfPlayGame, total)
+	for i := 0; i < total; i++ {
+		games <- selfPlayGame{board: opts.Seed + int64(i/opts.Games), rng: opts.Seed ^ int64(i)<<20}
+	}
+	close(games)
+
+	b := New()
+	var mu sync.Mutex
+	var firstErr error
+	done := 0
+
+	var wg sync.WaitGroup
+	for _, bot := range bots {
+		wg.Add(1)
+		go func(bot ai.Bot) {
+			defer wg.Done()
+			for sg := range games {
+				g := game.NewGameWithSeed(opts.Config, opts.Generator, sg.board)
+				played, err := selfPlay(g, bot, opts, rand.New(rand.NewSource(sg.rng)))
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				for _, p := range played {
+					b.Add(p.hash, p.move, score(g.GetState(), p.mover))
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, total)
+				}
+				mu.Unlock()
+			}
+		}(bot)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return b, nil
+}
+
+// openingMove is a move played in the opening of a self-play game
+type openingMove struct {
+	hash  uint64
+	move  game.Move
+	mover game.PlayerColor
+}
+
+// selfPlay plays the game out with the bot on both sides and returns its opening moves
+func selfPlay(g *game.MicemenGame, bot ai.Bot, opts BuildOptions, rng *rand.Rand) ([]openingMove, error) {
+	var opening []openingMove
+	for ply := 0; ply < maxGameMoves && !g.IsGameOver(); ply++ {
+		state := g.GetState()
+
+		var move game.Move
+		if ply < opts.Plies && rng.Float64() < opts.Explore {
+			legal := game.LegalMoves(state)
+			if len(legal) == 0 {
+				break
+			}
+			move = legal[rng.Intn(len(legal))]
+		} else {
+			var err error
+			move, err = bot.ChooseMove(g)
+			if err != nil {
+				return nil, fmt.Errorf("%s bot: %w", bot.Name(), err)
+			}
+		}
+
+		if err := g.ApplyMove(move); err != nil {
+			return nil, fmt.Errorf("%s bot played %s: %w", bot.Name(), move, err)
+		}
+		if ply < opts.Plies {
+			opening = append(opening, openingMove{hash: state.Hash, move: move, mover: state.CurrentPlayer})
+		}
+	}
+	return opening, nil
+}
+
+// score returns how the finished game went for a player: 1 for a win, 0 for a loss and
+// 0.5 for a game nobody won
+func score(state game.GameState, player game.PlayerColor) float64 {
+	switch state.Winner {
+	case player:
+		return 1
+	case game.NoPlayer:
+		return 0.5
+	default:
+		return 0
+	}
+}
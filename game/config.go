@@ -0,0 +1,89 @@
+package game
+
+import "fmt"
+
+// Default board configuration
+const (
+	DefaultGridWidth     = 19
+	DefaultGridHeight    = 13
+	DefaultMinWalls      = 5
+	DefaultMaxWalls      = 8
+	DefaultMicePerPlayer = 12
+	DefaultPlayerColumns = 9 // Columns on each side where a player's mice start
+)
+
+// Config describes the board a game is played on
+type Config struct {
+	Width          int  `json:"width"`
+	Height         int  `json:"height"`
+	MinWalls       int  `json:"minWalls"` // Fewest walls in a column
+	MaxWalls       int  `json:"maxWalls"` // Most walls in a column
+	MicePerPlayer  int  `json:"micePerPlayer"`
+	Player1Columns int  `json:"player1Columns"`      // Left-most columns for Player 1
+	Player2Columns int  `json:"player2Columns"`      // Right-most columns for Player 2
+	Symmetric      bool `json:"symmetric,omitempty"` // Mirror the left half of the board onto the right half
+}
+
+// DefaultConfig returns the standard 19x13 board with 12 mice per player
+func DefaultConfig() Config {
+	return Config{
+		Width:          DefaultGridWidth,
+		Height:         DefaultGridHeight,
+		MinWalls:       DefaultMinWalls,
+		MaxWalls:       DefaultMaxWalls,
+		MicePerPlayer:  DefaultMicePerPlayer,
+		Player1Columns: DefaultPlayerColumns,
+		Player2Columns: DefaultPlayerColumns,
+	}
+}
+
+// NewConfig returns a config for a board of the given size, scaling walls and starting
+// columns in proportion to the default board. A micePerPlayer of 0 scales that too.
+func NewConfig(width, height, micePerPlayer int) Config {
+	// Each player gets half the board, leaving the middle column neutral
+	playerColumns := (width - 1) / 2
+
+	if micePerPlayer <= 0 {
+		micePerPlayer = playerColumns * DefaultMicePerPlayer / DefaultPlayerColumns
+	}
+
+	return Config{
+		Width:          width,
+		Height:         height,
+		MinWalls:       height * DefaultMinWalls / DefaultGridHeight,
+		MaxWalls:       height * DefaultMaxWalls / DefaultGridHeight,
+		MicePerPlayer:  micePerPlayer,
+		Player1Columns: playerColumns,
+		Player2Columns: playerColumns,
+	}
+}
+
+// Validate checks that a board can be generated from the config
+func (c Config) Validate() error {
+	if c.Width < 3 || c.Height < 2 {
+		return fmt.Errorf("board must be at least 3x2, got %dx%d", c.Width, c.Height)
+	}
+	if c.MinWalls < 0 || c.MinWalls > c.MaxWalls || c.MaxWalls >= c.Height {
+		return fmt.Errorf("walls per column must satisfy 0 <= min <= max < height, got %d-%d for height %d",
+			c.MinWalls, c.MaxWalls, c.Height)
+	}
+	if c.Player1Columns < 1 || c.Player2Columns < 1 || c.Player1Columns+c.Player2Columns > c.Width {
+		return fmt.Errorf("player columns %d and %d don't fit on a board %d wide",
+			c.Player1Columns, c.Player2Columns, c.Width)
+	}
+	if c.Symmetric && c.Player1Columns != c.Player2Columns {
+		return fmt.Errorf("symmetric boards need the same number of columns per player, got %d and %d",
+			c.Player1Columns, c.Player2Columns)
+	}
+	if c.MicePerPlayer < 1 {
+		return fmt.Errorf("each player needs at least one mouse, got %d", c.MicePerPlayer)
+	}
+
+	// Every column keeps at least Height-MaxWalls free cells for mice to stand in
+	capacity := min(c.Player1Columns, c.Player2Columns) * (c.Height - c.MaxWalls)
+	if c.MicePerPlayer > capacity {
+		return fmt.Errorf("%d mice per player won't fit in %d free cells", c.MicePerPlayer, capacity)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package game
+
+import "fmt"
+
+// CheckFairness reports how a board favours one side, or nil if Red and Blue face the
+// same position. A fair board has the same number of mice on each side, and each side's
+// walls and mice are the mirror image of the other's.
+func CheckFairness(state GameState) error {
+	cfg := state.Config
+
+	// Both sides need the same number of mice still to bring home
+	var onBoard [2]int
+	for _, mouse := range state.Mice {
+		onBoard[mouse.Player]++
+	}
+	if onBoard[Red] != onBoard[Blue] || state.Escaped[Red] != state.Escaped[Blue] {
+		return fmt.Errorf("mouse counts differ: Red has %d on the board and %d home, Blue %d and %d",
+			onBoard[Red], state.Escaped[Red], onBoard[Blue], state.Escaped[Blue])
+	}
+
+	// Each column's walls must match its mirror column's
+	for col := 0; col < cfg.Width/2; col++ {
+		mirrorCol := cfg.Width - 1 - col
+		for row := 0; row < cfg.Height; row++ {
+			if state.Grid[row][col] != state.Grid[row][mirrorCol] {
+				return fmt.Errorf("walls differ between columns %d and %d at row %d", col, mirrorCol, row)
+			}
+		}
+	}
+
+	// Every mouse needs an opposing mouse at its mirror position
+	occupant := make(map[Position]PlayerColor, len(state.Mice))
+	for _, mouse := range state.Mice {
+		occupant[mouse.Position] = mouse.Player
+	}
+	for _, mouse := range state.Mice {
+		mirror := Position{Row: mouse.Position.Row, Col: cfg.Width - 1 - mouse.Position.Col}
+		if player, ok := occupant[mirror]; !ok || player != mouse.Player.Opponent() {
+			return fmt.Errorf("%s mouse at row %d, column %d has no %s mouse at its mirror position",
+				mouse.Player, mouse.Position.Row, mouse.Position.Col, mouse.Player.Opponent())
+		}
+	}
+
+	return nil
+}
@@ -1,38 +1,96 @@
 package game
 
 import (
+	"fmt"
 	"math/rand"
+	"slices"
 	"time"
 )
 
 // MicemenGame implements the Game interface
 type MicemenGame struct {
-	state GameState
+	state     GameState
+	config    Config
+	generator BoardGenerator
+	seed      int64
+	rng       *rand.Rand // Per-game source so boards can be reproduced from the seed
+
+	history []GameState // States before each move, most recent last
+	future  []GameState // States taken back with Undo, most recent last
+}
+
+// NewGame creates a new game instance with a random board laid out by the given generator.
+// The config should have been checked with Validate.
+func NewGame(cfg Config, gen BoardGenerator) *MicemenGame {
+	return NewGameWithSeed(cfg, gen, time.Now().UnixNano())
+}
+
+// NewGameWithSeed creates a new game instance whose board is generated from the given seed
+func NewGameWithSeed(cfg Config, gen BoardGenerator, seed int64) *MicemenGame {
+	game := &MicemenGame{config: cfg, generator: gen, seed: seed}
+	game.Reset()
+	return game
 }
 
-// NewGame creates a new game instance
-func NewGame() *MicemenGame {
-	game := &MicemenGame{}
+// NewGameFromState creates a game that starts from a saved state, such as one loaded from
+// JSON, instead of generating a board. Reset goes back to that state.
+func NewGameFromState(state GameState) *MicemenGame {
+	game := &MicemenGame{config: state.Config, generator: stateGenerator{state.Clone()}, seed: state.Seed}
 	game.Reset()
 	return game
 }
 
-// Reset initializes a new game
+// stateGenerator "generates" a board by copying a saved state, keeping the name of the
+// generator that laid out the original board
+type stateGenerator struct {
+	state GameState
+}
+
+// Name returns the name of the generator behind the saved board
+func (s stateGenerator) Name() string {
+	return s.state.Generator
+}
+
+// Generate copies the saved state
+func (s stateGenerator) Generate(state *GameState, rng *rand.Rand) {
+	*state = s.state.Clone()
+}
+
+// Reset initializes a new game, regenerating the same board from the game's seed
 func (g *MicemenGame) Reset() {
+	g.rng = rand.New(rand.NewSource(g.seed))
+	g.history = nil
+	g.future = nil
 	g.state = GameState{
-		SelectedColumn: GridWidth / 2,
+		Config:         g.config,
+		Generator:      g.generator.Name(),
+		Seed:           g.seed,
+		SelectedColumn: g.config.Width / 2,
 		GameOver:       false,
 		CurrentPlayer:  Red, // Red player starts
-		Mice:           make([]Mouse, 0, MicePerPlayer*2),
+		Mice:           make([]Mouse, 0, g.config.MicePerPlayer*2),
+		Winner:         NoPlayer,
+		Result:         ResultInProgress,
+
+		LastShiftColumn:    -1,
+		LastShiftDirection: DirectionNone,
 	}
-	g.generateWalls()
-	g.placeMice()
+	g.generateBoard()
+	g.state.Hash = g.state.ComputeHash()
 	g.moveToValidColumn() // Start on a valid column for current player
 }
 
+// generateBoard lays out walls and mice. Symmetric boards are fair by construction, since
+// PopulateBoard mirrors Red's half onto Blue's.
+func (g *MicemenGame) generateBoard() {
+	g.state.Grid = newGrid(g.config.Width, g.config.Height)
+	g.state.Mice = g.state.Mice[:0]
+	g.generator.Generate(&g.state, g.rng)
+}
+
 // GetState returns a copy of the current game state
 func (g *MicemenGame) GetState() GameState {
-	return g.state
+	return g.state.Clone()
 }
 
 // IsGameOver returns whether the game has ended
@@ -56,13 +114,7 @@ func (g *MicemenGame) GetPlayer(color PlayerColor) Player {
 
 // GetMiceAt returns all mice at the given position
 func (g *MicemenGame) GetMiceAt(pos Position) []Mouse {
-	var mice []Mouse
-	for _, mouse := range g.state.Mice {
-		if mouse.Position.Row == pos.Row && mouse.Position.Col == pos.Col {
-			mice = append(mice, mouse)
-		}
-	}
-	return mice
+	return g.state.MiceAt(pos)
 }
 
 // ProcessAction handles a player action
@@ -77,17 +129,120 @@ func (g *MicemenGame) ProcessAction(action Action) {
 	case ActionMoveRight:
 		g.moveSelectionToValidColumn(1)
 	case ActionMoveColumnUp:
-		if g.canPlayerMoveColumn(g.state.CurrentPlayer, g.state.SelectedColumn) {
-			g.moveColumnUp()
-			g.switchPlayer()
-		}
+		g.ApplyMove(Move{Column: g.state.SelectedColumn, Direction: DirectionUp})
 	case ActionMoveColumnDown:
-		if g.canPlayerMoveColumn(g.state.CurrentPlayer, g.state.SelectedColumn) {
-			g.moveColumnDown()
-			g.switchPlayer()
-		}
+		g.ApplyMove(Move{Column: g.state.SelectedColumn, Direction: DirectionDown})
 	case ActionQuit:
 		g.state.GameOver = true
+		g.state.Result = ResultQuit
+	case ActionUndo:
+		g.Undo()
+	case ActionRedo:
+		g.Redo()
+	}
+}
+
+// Undo takes back the last move. It returns false if there's nothing to take back.
+func (g *MicemenGame) Undo() bool {
+	if len(g.history) == 0 {
+		return false
+	}
+
+	g.future = append(g.future, g.state)
+	g.state = g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	return true
+}
+
+// Redo replays a move taken back with Undo. It returns false if there's nothing to replay.
+func (g *MicemenGame) Redo() bool {
+	if len(g.future) == 0 {
+		return false
+	}
+
+	g.history = append(g.history, g.state)
+	g.state = g.future[len(g.future)-1]
+	g.future = g.future[:len(g.future)-1]
+	return true
+}
+
+// Moves returns the moves played so far, not counting moves taken back with Undo
+func (g *MicemenGame) Moves() []Move {
+	moves := make([]Move, len(g.history))
+	for i := range g.history {
+		// Each state remembers the shift that produced it
+		next := g.state
+		if i+1 < len(g.history) {
+			next = g.history[i+1]
+		}
+		moves[i] = Move{Column: next.LastShiftColumn, Direction: next.LastShiftDirection}
+	}
+	return moves
+}
+
+// InitialState returns a copy of the state before the first move
+func (g *MicemenGame) InitialState() GameState {
+	if len(g.history) == 0 {
+		return g.GetState()
+	}
+	return g.history[0].Clone()
+}
+
+// HistoryDepth returns how many moves can be undone and redone
+func (g *MicemenGame) HistoryDepth() (undo, redo int) {
+	return len(g.history), len(g.future)
+}
+
+// ApplyMove shifts a column for the player to move without going through cursor
+// navigation, then lets the board settle and hands the turn over
+func (g *MicemenGame) ApplyMove(m Move) error {
+	if err := g.checkMove(m); err != nil {
+		return err
+	}
+
+	// Snapshot the board so the move can be taken back; a new move abandons any redos
+	g.history = append(g.history, g.state.Clone())
+	g.future = nil
+
+	g.playMove(m)
+	return nil
+}
+
+// checkMove returns why the player to move can't make the move, or nil if they can
+func (g *MicemenGame) checkMove(m Move) error {
+	if g.state.GameOver {
+		return ErrGameOver
+	}
+	if m.Direction != DirectionUp && m.Direction != DirectionDown {
+		return fmt.Errorf("%w: %v has no direction", ErrIllegalMove, m)
+	}
+	if !g.canPlayerMoveColumn(g.state.CurrentPlayer, m.Column) {
+		return fmt.Errorf("%w: %s can't move column %d", ErrIllegalMove, g.state.CurrentPlayer, m.Column+1)
+	}
+	if g.isMoveLocked(g.state.CurrentPlayer, m) {
+		return fmt.Errorf("%w: %s can't shift column %d straight back", ErrIllegalMove, g.state.CurrentPlayer, m.Column+1)
+	}
+	return nil
+}
+
+// playMove makes a legal move: shifts the column, lets the board settle and hands the
+// turn over
+func (g *MicemenGame) playMove(m Move) {
+	g.state.SelectedColumn = m.Column
+	if m.Direction == DirectionUp {
+		g.moveColumnUp()
+	} else {
+		g.moveColumnDown()
+	}
+	g.state.hashLastShift()
+	g.state.LastShiftColumn = m.Column
+	g.state.LastShiftDirection = m.Direction
+	g.state.hashLastShift()
+
+	g.applyGravity()
+	g.resolveMovement(g.state.CurrentPlayer)
+	if !g.state.GameOver {
+		g.switchPlayer()
 	}
 }
 
@@ -96,51 +251,58 @@ func (g *MicemenGame) CanPlayerMoveColumn(player PlayerColor, col int) bool {
 	return g.canPlayerMoveColumn(player, col)
 }
 
-// GetValidColumnsForPlayer returns all columns where the player has mice (public method)
+// GetValidColumnsForPlayer returns all columns the player is allowed to move (public method)
 func (g *MicemenGame) GetValidColumnsForPlayer(player PlayerColor) []int {
 	return g.getValidColumnsForPlayer(player)
 }
 
-// canPlayerMoveColumn checks if the current player can move the specified column
+// canPlayerMoveColumn checks if the player can move the specified column in at least one
+// direction
 func (g *MicemenGame) canPlayerMoveColumn(player PlayerColor, col int) bool {
-	if col < 0 || col >= GridWidth {
+	if col < 0 || col >= g.state.Config.Width {
 		return false
 	}
 
 	// Check if the player has any mice in this column
+	hasMice := false
 	for _, mouse := range g.state.Mice {
 		if mouse.Position.Col == col && mouse.Player == player {
-			return true
+			hasMice = true
+			break
 		}
 	}
 
-	return false
+	return hasMice
 }
 
-// getValidColumnsForPlayer returns all columns where the player has mice
-func (g *MicemenGame) getValidColumnsForPlayer(player PlayerColor) []int {
-	columnSet := make(map[int]bool)
+// isMoveLocked checks if the move shifts the column the opponent just shifted back the
+// other way. That's forbidden so a move can't be undone straight away; shifting the column
+// on in the same direction is fine.
+func (g *MicemenGame) isMoveLocked(player PlayerColor, m Move) bool {
+	if player != g.state.CurrentPlayer || m.Column != g.state.LastShiftColumn {
+		return false
+	}
+	return m.Direction != g.state.LastShiftDirection
+}
 
+// getValidColumnsForPlayer returns all columns the player is allowed to move in at least
+// one direction, in order
+func (g *MicemenGame) getValidColumnsForPlayer(player PlayerColor) []int {
+	hasMice := make([]bool, g.state.Config.Width)
+	count := 0
 	for _, mouse := range g.state.Mice {
-		if mouse.Player == player {
-			columnSet[mouse.Position.Col] = true
+		if mouse.Player == player && !hasMice[mouse.Position.Col] {
+			hasMice[mouse.Position.Col] = true
+			count++
 		}
 	}
 
-	var columns []int
-	for col := range columnSet {
-		columns = append(columns, col)
-	}
-
-	// Sort columns for consistent ordering
-	for i := 0; i < len(columns)-1; i++ {
-		for j := i + 1; j < len(columns); j++ {
-			if columns[i] > columns[j] {
-				columns[i], columns[j] = columns[j], columns[i]
-			}
+	columns := make([]int, 0, count)
+	for col, ok := range hasMice {
+		if ok {
+			columns = append(columns, col)
 		}
 	}
-
 	return columns
 }
 
@@ -225,145 +387,67 @@ func (g *MicemenGame) switchPlayer() {
 	} else {
 		g.state.CurrentPlayer = Red
 	}
+	g.state.hashTurn()
 
 	// Move to a valid column for the new player
 	g.moveToValidColumn()
 }
 
-// generateWalls randomly places walls in each column
-func (g *MicemenGame) generateWalls() {
-	rand.Seed(time.Now().UnixNano())
-
-	for col := 0; col < GridWidth; col++ {
-		// Random number of walls for this column
-		numWalls := rand.Intn(MaxWalls-MinWalls+1) + MinWalls
-
-		// Generate random positions for walls
-		positions := make(map[int]bool)
-		for len(positions) < numWalls {
-			pos := rand.Intn(GridHeight)
-			positions[pos] = true
-		}
-
-		// Place walls at selected positions
-		for row := 0; row < GridHeight; row++ {
-			if positions[row] {
-				g.state.Grid[row][col] = Wall
-			} else {
-				g.state.Grid[row][col] = Empty
-			}
-		}
-	}
+// isValidMousePosition checks if a mouse can be placed at the given position
+func (g *MicemenGame) isValidMousePosition(pos Position) bool {
+	return g.state.IsValidMousePosition(pos)
 }
 
-// placeMice randomly places mice for both players
-func (g *MicemenGame) placeMice() {
-	rand.Seed(time.Now().UnixNano())
-
-	// Place Red player's mice (left 9 columns)
-	g.placeMiceForPlayer(Red, 0, Player1Columns-1)
-
-	// Place Blue player's mice (right 9 columns)
-	g.placeMiceForPlayer(Blue, GridWidth-Player2Columns, GridWidth-1)
+// isSupported checks if the cell below the given position can hold a mouse up
+func (g *MicemenGame) isSupported(pos Position) bool {
+	return g.state.isSupported(pos)
 }
 
-// placeMiceForPlayer places mice for a specific player in the given column range
-func (g *MicemenGame) placeMiceForPlayer(player PlayerColor, startCol, endCol int) {
-	for i := 0; i < MicePerPlayer; i++ {
-		// Find a valid position for this mouse
-		pos := g.findValidMousePosition(startCol, endCol)
-		if pos != nil {
-			mouse := Mouse{
-				Position: *pos,
-				Player:   player,
+// applyGravity drops unsupported mice one row at a time until the board is stable
+func (g *MicemenGame) applyGravity() {
+	for {
+		fell := false
+
+		// Work from the bottom up so a stack of mice falls together
+		for row := g.state.Config.Height - 2; row >= 0; row-- {
+			for i := range g.state.Mice {
+				mouse := &g.state.Mice[i]
+				if mouse.Position.Row != row || g.isSupported(mouse.Position) {
+					continue
+				}
+				g.state.hashMouse(*mouse)
+				mouse.Position.Row++
+				g.state.hashMouse(*mouse)
+				fell = true
 			}
-			g.state.Mice = append(g.state.Mice, mouse)
 		}
-	}
-}
-
-// findValidMousePosition finds a valid position for a mouse in the given column range
-func (g *MicemenGame) findValidMousePosition(startCol, endCol int) *Position {
-	maxAttempts := 1000 // Prevent infinite loops
-	attempts := 0
-
-	for attempts < maxAttempts {
-		attempts++
-
-		// Random column in range
-		col := startCol + rand.Intn(endCol-startCol+1)
 
-		// Find valid rows in this column (must be above a wall or another mouse)
-		validRows := g.getValidRowsForMouse(col)
-		if len(validRows) == 0 {
-			continue
+		if !fell {
+			return
 		}
-
-		// Pick a random valid row
-		row := validRows[rand.Intn(len(validRows))]
-		return &Position{Row: row, Col: col}
 	}
-
-	return nil // Could not find valid position
-}
-
-// getValidRowsForMouse returns all valid rows where a mouse can be placed in the given column
-func (g *MicemenGame) getValidRowsForMouse(col int) []int {
-	var validRows []int
-
-	for row := 0; row < GridHeight; row++ {
-		if g.isValidMousePosition(Position{Row: row, Col: col}) {
-			validRows = append(validRows, row)
-		}
-	}
-
-	return validRows
-}
-
-// isValidMousePosition checks if a mouse can be placed at the given position
-func (g *MicemenGame) isValidMousePosition(pos Position) bool {
-	// Check if position is within bounds
-	if pos.Row < 0 || pos.Row >= GridHeight || pos.Col < 0 || pos.Col >= GridWidth {
-		return false
-	}
-
-	// Mouse must be placed directly above a wall or another mouse
-	if pos.Row == GridHeight-1 {
-		// Bottom row: must be above a wall
-		return g.state.Grid[pos.Row][pos.Col] == Wall
-	}
-
-	// Check if there's support below (wall or mouse)
-	belowPos := Position{Row: pos.Row + 1, Col: pos.Col}
-
-	// Check for wall below
-	if g.state.Grid[belowPos.Row][belowPos.Col] == Wall {
-		return true
-	}
-
-	// Check for mouse below
-	miceBelow := g.GetMiceAt(belowPos)
-	return len(miceBelow) > 0
 }
 
 // moveColumnUp shifts all cells in the selected column up (with wraparound) and updates mouse positions
 func (g *MicemenGame) moveColumnUp() {
-	if g.state.SelectedColumn < 0 || g.state.SelectedColumn >= GridWidth {
+	if g.state.SelectedColumn < 0 || g.state.SelectedColumn >= g.state.Config.Width {
 		return
 	}
 
 	col := g.state.SelectedColumn
 
 	// Store the top cell
+	g.state.hashColumnWalls(col)
 	topCell := g.state.Grid[0][col]
 
 	// Shift all cells up
-	for row := 0; row < GridHeight-1; row++ {
+	for row := 0; row < g.state.Config.Height-1; row++ {
 		g.state.Grid[row][col] = g.state.Grid[row+1][col]
 	}
 
 	// Wrap the top cell to the bottom
-	g.state.Grid[GridHeight-1][col] = topCell
+	g.state.Grid[g.state.Config.Height-1][col] = topCell
+	g.state.hashColumnWalls(col)
 
 	// Update mouse positions in this column
 	g.updateMiceForColumnShift(col, true)
@@ -371,22 +455,24 @@ func (g *MicemenGame) moveColumnUp() {
 
 // moveColumnDown shifts all cells in the selected column down (with wraparound) and updates mouse positions
 func (g *MicemenGame) moveColumnDown() {
-	if g.state.SelectedColumn < 0 || g.state.SelectedColumn >= GridWidth {
+	if g.state.SelectedColumn < 0 || g.state.SelectedColumn >= g.state.Config.Width {
 		return
 	}
 
 	col := g.state.SelectedColumn
 
 	// Store the bottom cell
-	bottomCell := g.state.Grid[GridHeight-1][col]
+	g.state.hashColumnWalls(col)
+	bottomCell := g.state.Grid[g.state.Config.Height-1][col]
 
 	// Shift all cells down
-	for row := GridHeight - 1; row > 0; row-- {
+	for row := g.state.Config.Height - 1; row > 0; row-- {
 		g.state.Grid[row][col] = g.state.Grid[row-1][col]
 	}
 
 	// Wrap the bottom cell to the top
 	g.state.Grid[0][col] = bottomCell
+	g.state.hashColumnWalls(col)
 
 	// Update mouse positions in this column
 	g.updateMiceForColumnShift(col, false)
@@ -397,21 +483,137 @@ func (g *MicemenGame) updateMiceForColumnShift(col int, shiftUp bool) {
 	for i := range g.state.Mice {
 		mouse := &g.state.Mice[i]
 		if mouse.Position.Col == col {
+			g.state.hashMouse(*mouse)
 			if shiftUp {
 				// Shift up: row decreases, with wraparound
 				if mouse.Position.Row == 0 {
-					mouse.Position.Row = GridHeight - 1
+					mouse.Position.Row = g.state.Config.Height - 1
 				} else {
 					mouse.Position.Row--
 				}
 			} else {
 				// Shift down: row increases, with wraparound
-				if mouse.Position.Row == GridHeight-1 {
+				if mouse.Position.Row == g.state.Config.Height-1 {
 					mouse.Position.Row = 0
 				} else {
 					mouse.Position.Row++
 				}
 			}
+			g.state.hashMouse(*mouse)
 		}
 	}
 }
+
+// walkDirection returns the column step a player's mice take toward the opponent's side
+func walkDirection(player PlayerColor) int {
+	if player == Red {
+		return 1
+	}
+	return -1
+}
+
+// stepResult describes the outcome of a single mouse step
+type stepResult int
+
+const (
+	stepBlocked stepResult = iota
+	stepMoved
+	stepEscaped
+)
+
+// resolveMovement lets mice walk toward the opponent's side until none of them can move.
+// The mover's mice go first, so the outcome of a turn doesn't depend on slice order.
+func (g *MicemenGame) resolveMovement(mover PlayerColor) {
+	// A mouse that moved may have freed the way for one that was already blocked
+	for !g.state.GameOver && g.walkPass(mover) {
+	}
+}
+
+// walkPass walks each mouse in resolution order as far as it can go and reports whether
+// any of them moved. The pass ends early when a mouse escapes, as that reorders the mice.
+func (g *MicemenGame) walkPass(mover PlayerColor) bool {
+	walked := false
+
+	for _, i := range g.walkOrder(mover) {
+		// Each mouse keeps going until it's blocked before the next one starts
+		result := g.stepMouse(i)
+		for result == stepMoved {
+			g.applyGravity()
+			walked = true
+			result = g.stepMouse(i)
+		}
+
+		if result == stepEscaped {
+			g.escapeMouse(i)
+			g.applyGravity() // Anything standing on the mouse falls
+			return true
+		}
+	}
+
+	return walked
+}
+
+// escapeMouse takes a mouse off the board, scores it for its owner and checks for a winner
+func (g *MicemenGame) escapeMouse(i int) {
+	player := g.state.Mice[i].Player
+	g.state.hashMouse(g.state.Mice[i])
+	g.state.Mice = append(g.state.Mice[:i], g.state.Mice[i+1:]...)
+	g.state.Escaped[player]++
+
+	// First player to get every mouse home wins
+	if g.state.Escaped[player] >= g.state.Config.MicePerPlayer {
+		g.state.GameOver = true
+		g.state.Winner = player
+		g.state.Result = ResultAllMiceHome
+	}
+}
+
+// walkOrder returns mouse indices in resolution order: the mover's mice before the
+// opponent's, the mouse furthest along first, and the lower mouse first on a tie
+func (g *MicemenGame) walkOrder(mover PlayerColor) []int {
+	order := make([]int, len(g.state.Mice))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		ma, mb := g.state.Mice[a], g.state.Mice[b]
+		if ma.Player != mb.Player {
+			if ma.Player == mover {
+				return -1
+			}
+			return 1
+		}
+		if ma.Position.Col != mb.Position.Col {
+			// Furthest along means furthest in the direction the mouse walks
+			return (mb.Position.Col - ma.Position.Col) * walkDirection(ma.Player)
+		}
+		return mb.Position.Row - ma.Position.Row
+	})
+
+	return order
+}
+
+// stepMouse moves a mouse one cell toward the opponent's side if nothing is in its way
+func (g *MicemenGame) stepMouse(i int) stepResult {
+	mouse := &g.state.Mice[i]
+	next := Position{Row: mouse.Position.Row, Col: mouse.Position.Col + walkDirection(mouse.Player)}
+
+	// Walking past the far edge takes the mouse home
+	if next.Col < 0 || next.Col >= g.state.Config.Width {
+		return stepEscaped
+	}
+
+	// Stopped by a wall or another mouse
+	if g.state.Grid[next.Row][next.Col] == Wall {
+		return stepBlocked
+	}
+	if g.state.hasMouseAt(next) {
+		return stepBlocked
+	}
+
+	g.state.hashMouse(*mouse)
+	mouse.Position = next
+	g.state.hashMouse(*mouse)
+	return stepMoved
+}
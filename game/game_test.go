@@ -1,16 +1,19 @@
 package game
 
 import (
+	"errors"
+	"math/rand"
 	"testing"
 )
 
 func TestNewGame(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	state := game.GetState()
 	
 	// Test initial state
-	if state.SelectedColumn != GridWidth/2 {
-		t.Errorf("Initial selected column should be %d, got %d", GridWidth/2, state.SelectedColumn)
+	if state.SelectedColumn < 0 || state.SelectedColumn >= cfg.Width {
+		t.Errorf("Initial selected column should be on the board, got %d", state.SelectedColumn)
 	}
 	
 	if state.GameOver {
@@ -22,11 +25,11 @@ func TestNewGame(t *testing.T) {
 	}
 	
 	// Verify grid dimensions are correct
-	if len(state.Grid) != GridHeight {
-		t.Errorf("Grid height should be %d, got %d", GridHeight, len(state.Grid))
+	if len(state.Grid) != cfg.Height {
+		t.Errorf("Grid height should be %d, got %d", cfg.Height, len(state.Grid))
 	}
-	if len(state.Grid[0]) != GridWidth {
-		t.Errorf("Grid width should be %d, got %d", GridWidth, len(state.Grid[0]))
+	if len(state.Grid[0]) != cfg.Width {
+		t.Errorf("Grid width should be %d, got %d", cfg.Width, len(state.Grid[0]))
 	}
 }
 
@@ -40,7 +43,7 @@ func TestPlayerColors(t *testing.T) {
 }
 
 func TestTurnBasedMovement(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	
 	// Test that only valid columns can be moved
 	originalPlayer := game.GetState().CurrentPlayer
@@ -53,7 +56,7 @@ func TestTurnBasedMovement(t *testing.T) {
 	
 	// Set selection to a valid column and try to move
 	game.state.SelectedColumn = validColumns[0]
-	originalGrid := game.state.Grid
+	originalGrid := game.GetState().Grid
 	
 	game.ProcessAction(ActionMoveColumnUp)
 	newState := game.GetState()
@@ -64,13 +67,113 @@ func TestTurnBasedMovement(t *testing.T) {
 	}
 	
 	// Grid should have changed
-	if game.state.Grid == originalGrid {
+	if gridsEqual(game.state.Grid, originalGrid) {
 		t.Error("Grid should have changed after move")
 	}
 }
 
+// setupLockedColumnBoard boxes mice in with full-height walls so they can't walk away.
+// Red has mice in columns 3 and 5, Blue in columns 5 and 10.
+func setupLockedColumnBoard(game *MicemenGame) {
+	clearBoard(game)
+	cfg := game.state.Config
+	for row := 0; row < cfg.Height; row++ {
+		game.state.Grid[row][4] = Wall
+		game.state.Grid[row][6] = Wall
+		game.state.Grid[row][9] = Wall
+	}
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: cfg.Height - 1, Col: 3}, Player: Red},
+		{Position: Position{Row: cfg.Height - 1, Col: 5}, Player: Red},
+		{Position: Position{Row: cfg.Height - 2, Col: 5}, Player: Blue},
+		{Position: Position{Row: cfg.Height - 1, Col: 10}, Player: Blue},
+	}
+	game.state.CurrentPlayer = Red
+	game.state.SelectedColumn = 5
+}
+
+func TestCannotMoveOpponentsLastColumn(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	setupLockedColumnBoard(game)
+	
+	game.ProcessAction(ActionMoveColumnUp)
+	state := game.GetState()
+	if state.CurrentPlayer != Blue {
+		t.Fatal("Red's move should have been accepted")
+	}
+	if state.LastShiftColumn != 5 || state.LastShiftDirection != DirectionUp {
+		t.Errorf("Last shift should be column 5 up, got column %d %v",
+			state.LastShiftColumn, state.LastShiftDirection)
+	}
+	
+	// Blue may still shift column 5, but only further up, so it can't be shifted straight back
+	if !game.canPlayerMoveColumn(Blue, 5) {
+		t.Error("Blue should be able to move the column Red just shifted in the same direction")
+	}
+	validColumns := game.getValidColumnsForPlayer(Blue)
+	if len(validColumns) != 2 || validColumns[0] != 5 || validColumns[1] != 10 {
+		t.Errorf("Blue's valid columns should be 5 and 10, got %v", validColumns)
+	}
+	if err := game.checkMove(Move{Column: 5, Direction: DirectionDown}); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Shifting column 5 back down should be illegal, got %v", err)
+	}
+	
+	// Forcing the selection onto the locked column must not move anything
+	game.state.SelectedColumn = 5
+	originalMice := append([]Mouse(nil), game.state.Mice...)
+	game.ProcessAction(ActionMoveColumnDown)
+	if game.GetState().CurrentPlayer != Blue {
+		t.Error("Player should not switch after trying to undo the last move")
+	}
+	for i, mouse := range game.state.Mice {
+		if mouse != originalMice[i] {
+			t.Errorf("Mouse %d should not move, was %v now %v", i, originalMice[i], mouse)
+		}
+	}
+	
+	// The lock only lasts one turn
+	game.state.SelectedColumn = 10
+	game.ProcessAction(ActionMoveColumnUp)
+	if !game.canPlayerMoveColumn(Red, 5) {
+		t.Error("Red should be able to move column 5 again once Blue has moved elsewhere")
+	}
+}
+
+func TestShiftingOnInSameDirection(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	setupLockedColumnBoard(game)
+	
+	// Without the mouse in column 10, column 5 is Blue's only option
+	game.state.Mice = game.state.Mice[:3]
+	game.ProcessAction(ActionMoveColumnDown)
+	
+	if !game.canPlayerMoveColumn(Blue, 5) {
+		t.Error("Blue should be allowed to move column 5 on in the direction Red shifted it")
+	}
+	
+	// Shifting it back up is still off limits, even with no other column
+	game.ProcessAction(ActionMoveColumnUp)
+	if game.GetState().CurrentPlayer != Blue {
+		t.Error("Blue shouldn't be able to shift column 5 back up")
+	}
+	
+	// Shifting it on down is a normal move, which Red may not reverse in turn
+	game.ProcessAction(ActionMoveColumnDown)
+	state := game.GetState()
+	if state.CurrentPlayer != Red {
+		t.Fatal("Blue should be able to shift column 5 further down")
+	}
+	if state.LastShiftColumn != 5 || state.LastShiftDirection != DirectionDown {
+		t.Errorf("Last shift should be column 5 down, got column %d %v", state.LastShiftColumn, state.LastShiftDirection)
+	}
+	if err := game.checkMove(Move{Column: 5, Direction: DirectionUp}); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Red shifting column 5 back up should be illegal, got %v", err)
+	}
+}
+
 func TestInvalidColumnMovement(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	
 	// Find a column that the current player cannot move
 	currentPlayer := game.GetState().CurrentPlayer
@@ -78,7 +181,7 @@ func TestInvalidColumnMovement(t *testing.T) {
 	
 	// Find an invalid column
 	invalidCol := -1
-	for col := 0; col < GridWidth; col++ {
+	for col := 0; col < cfg.Width; col++ {
 		isValid := false
 		for _, validCol := range validColumns {
 			if col == validCol {
@@ -99,7 +202,7 @@ func TestInvalidColumnMovement(t *testing.T) {
 	// Try to move invalid column
 	game.state.SelectedColumn = invalidCol
 	originalPlayer := game.GetState().CurrentPlayer
-	originalGrid := game.state.Grid
+	originalGrid := game.GetState().Grid
 	
 	game.ProcessAction(ActionMoveColumnUp)
 	newState := game.GetState()
@@ -110,13 +213,13 @@ func TestInvalidColumnMovement(t *testing.T) {
 	}
 	
 	// Grid should NOT have changed
-	if game.state.Grid != originalGrid {
+	if !gridsEqual(game.state.Grid, originalGrid) {
 		t.Error("Grid should not change after invalid move")
 	}
 }
 
 func TestCanPlayerMoveColumn(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	
 	// Create a test scenario with known mice positions
 	game.state.Mice = []Mouse{
@@ -154,7 +257,7 @@ func TestCanPlayerMoveColumn(t *testing.T) {
 }
 
 func TestGetValidColumnsForPlayer(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	
 	// Create test scenario
 	game.state.Mice = []Mouse{
@@ -192,7 +295,7 @@ func TestGetValidColumnsForPlayer(t *testing.T) {
 }
 
 func TestMoveSelectionToValidColumn(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	
 	// Set up test scenario
 	game.state.Mice = []Mouse{
@@ -229,7 +332,7 @@ func TestMoveSelectionToValidColumn(t *testing.T) {
 }
 
 func TestSwitchPlayer(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	
 	// Set up known mice positions for both players
 	game.state.Mice = []Mouse{
@@ -267,7 +370,7 @@ func TestSwitchPlayer(t *testing.T) {
 }
 
 func TestInitialValidColumnSelection(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	state := game.GetState()
 	
 	// Initial selection should be on a valid column for the starting player
@@ -279,7 +382,8 @@ func TestInitialValidColumnSelection(t *testing.T) {
 // Original tests continue...
 
 func TestMicePlacement(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	state := game.GetState()
 	
 	// Count mice for each player
@@ -291,38 +395,39 @@ func TestMicePlacement(t *testing.T) {
 		case Red:
 			redMice++
 			// Red mice should be in left 9 columns
-			if mouse.Position.Col >= Player1Columns {
+			if mouse.Position.Col >= cfg.Player1Columns {
 				t.Errorf("Red mouse at column %d should be in columns 0-%d", 
-					mouse.Position.Col, Player1Columns-1)
+					mouse.Position.Col, cfg.Player1Columns-1)
 			}
 		case Blue:
 			blueMice++
 			// Blue mice should be in right 9 columns
-			if mouse.Position.Col < GridWidth-Player2Columns {
+			if mouse.Position.Col < cfg.Width-cfg.Player2Columns {
 				t.Errorf("Blue mouse at column %d should be in columns %d-%d", 
-					mouse.Position.Col, GridWidth-Player2Columns, GridWidth-1)
+					mouse.Position.Col, cfg.Width-cfg.Player2Columns, cfg.Width-1)
 			}
 		}
 	}
 	
 	// Should have correct number of mice per player
-	if redMice != MicePerPlayer {
-		t.Errorf("Should have %d red mice, got %d", MicePerPlayer, redMice)
+	if redMice != cfg.MicePerPlayer {
+		t.Errorf("Should have %d red mice, got %d", cfg.MicePerPlayer, redMice)
 	}
-	if blueMice != MicePerPlayer {
-		t.Errorf("Should have %d blue mice, got %d", MicePerPlayer, blueMice)
+	if blueMice != cfg.MicePerPlayer {
+		t.Errorf("Should have %d blue mice, got %d", cfg.MicePerPlayer, blueMice)
 	}
 }
 
 func TestMicePositionValidity(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	state := game.GetState()
 	
 	for _, mouse := range state.Mice {
 		pos := mouse.Position
 		
 		// Check bounds
-		if pos.Row < 0 || pos.Row >= GridHeight || pos.Col < 0 || pos.Col >= GridWidth {
+		if pos.Row < 0 || pos.Row >= cfg.Height || pos.Col < 0 || pos.Col >= cfg.Width {
 			t.Errorf("Mouse at invalid position: row=%d, col=%d", pos.Row, pos.Col)
 		}
 		
@@ -334,7 +439,8 @@ func TestMicePositionValidity(t *testing.T) {
 }
 
 func TestGetPlayer(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	
 	redPlayer := game.GetPlayer(Red)
 	bluePlayer := game.GetPlayer(Blue)
@@ -346,16 +452,16 @@ func TestGetPlayer(t *testing.T) {
 		t.Errorf("Blue player should have Blue color, got %v", bluePlayer.Color)
 	}
 	
-	if len(redPlayer.Mice) != MicePerPlayer {
-		t.Errorf("Red player should have %d mice, got %d", MicePerPlayer, len(redPlayer.Mice))
+	if len(redPlayer.Mice) != cfg.MicePerPlayer {
+		t.Errorf("Red player should have %d mice, got %d", cfg.MicePerPlayer, len(redPlayer.Mice))
 	}
-	if len(bluePlayer.Mice) != MicePerPlayer {
-		t.Errorf("Blue player should have %d mice, got %d", MicePerPlayer, len(bluePlayer.Mice))
+	if len(bluePlayer.Mice) != cfg.MicePerPlayer {
+		t.Errorf("Blue player should have %d mice, got %d", cfg.MicePerPlayer, len(bluePlayer.Mice))
 	}
 }
 
 func TestQuitAction(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	if game.IsGameOver() {
 		t.Error("Game should not be over initially")
 	}
@@ -367,7 +473,8 @@ func TestQuitAction(t *testing.T) {
 }
 
 func TestReset(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	
 	// Make some changes
 	game.ProcessAction(ActionMoveRight)
@@ -386,8 +493,8 @@ func TestReset(t *testing.T) {
 	}
 	
 	// Should have proper number of mice again
-	if len(state.Mice) != MicePerPlayer*2 {
-		t.Errorf("Should have %d total mice after reset, got %d", MicePerPlayer*2, len(state.Mice))
+	if len(state.Mice) != cfg.MicePerPlayer*2 {
+		t.Errorf("Should have %d total mice after reset, got %d", cfg.MicePerPlayer*2, len(state.Mice))
 	}
 	
 	// Initial selection should be valid for starting player
@@ -397,31 +504,29 @@ func TestReset(t *testing.T) {
 }
 
 func TestGenerateWalls(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	state := game.GetState()
 	
 	// Test that each column has the correct number of walls
-	for col := 0; col < GridWidth; col++ {
+	for col := 0; col < cfg.Width; col++ {
 		wallCount := 0
-		for row := 0; row < GridHeight; row++ {
+		for row := 0; row < cfg.Height; row++ {
 			if state.Grid[row][col] == Wall {
 				wallCount++
 			}
 		}
 		
-		if wallCount < MinWalls || wallCount > MaxWalls {
+		if wallCount < cfg.MinWalls || wallCount > cfg.MaxWalls {
 			t.Errorf("Column %d has %d walls, expected between %d and %d", 
-				col, wallCount, MinWalls, MaxWalls)
+				col, wallCount, cfg.MinWalls, cfg.MaxWalls)
 		}
 	}
-} %d", MicePerPlayer*2, len(state.Mice))
-	}
-} %d", MicePerPlayer*2, len(state.Mice))
-	}
 }
 
 func TestIsValidMousePosition(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	
 	// Test bounds checking
 	if game.isValidMousePosition(Position{Row: -1, Col: 0}) {
@@ -430,82 +535,17 @@ func TestIsValidMousePosition(t *testing.T) {
 	if game.isValidMousePosition(Position{Row: 0, Col: -1}) {
 		t.Error("Position with negative column should be invalid")
 	}
-	if game.isValidMousePosition(Position{Row: GridHeight, Col: 0}) {
+	if game.isValidMousePosition(Position{Row: cfg.Height, Col: 0}) {
 		t.Error("Position beyond grid height should be invalid")
 	}
-	if game.isValidMousePosition(Position{Row: 0, Col: GridWidth}) {
+	if game.isValidMousePosition(Position{Row: 0, Col: cfg.Width}) {
 		t.Error("Position beyond grid width should be invalid")
 	}
 }
 
-func TestGenerateWalls(t *testing.T) {
-	game := NewGame()
-	state := game.GetState()
-	
-	// Test that each column has the correct number of walls
-	for col := 0; col < GridWidth; col++ {
-		wallCount := 0
-		for row := 0; row < GridHeight; row++ {
-			if state.Grid[row][col] == Wall {
-				wallCount++
-			}
-		}
-		
-		if wallCount < MinWalls || wallCount > MaxWalls {
-			t.Errorf("Column %d has %d walls, expected between %d and %d", 
-				col, wallCount, MinWalls, MaxWalls)
-		}
-	}
-}package game
-
-import (
-	"testing"
-)
-
-func TestNewGame(t *testing.T) {
-	game := NewGame()
-	state := game.GetState()
-	
-	// Test initial state
-	if state.SelectedColumn != GridWidth/2 {
-		t.Errorf("Initial selected column should be %d, got %d", GridWidth/2, state.SelectedColumn)
-	}
-	
-	if state.GameOver {
-		t.Error("New game should not be over")
-	}
-	
-	// Verify grid dimensions are correct
-	if len(state.Grid) != GridHeight {
-		t.Errorf("Grid height should be %d, got %d", GridHeight, len(state.Grid))
-	}
-	if len(state.Grid[0]) != GridWidth {
-		t.Errorf("Grid width should be %d, got %d", GridWidth, len(state.Grid[0]))
-	}
-}
-
-func TestGenerateWalls(t *testing.T) {
-	game := NewGame()
-	state := game.GetState()
-	
-	// Test that each column has the correct number of walls
-	for col := 0; col < GridWidth; col++ {
-		wallCount := 0
-		for row := 0; row < GridHeight; row++ {
-			if state.Grid[row][col] == Wall {
-				wallCount++
-			}
-		}
-		
-		if wallCount < MinWalls || wallCount > MaxWalls {
-			t.Errorf("Column %d has %d walls, expected between %d and %d", 
-				col, wallCount, MinWalls, MaxWalls)
-		}
-	}
-}
-
 func TestMoveColumnUp(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	
 	// Set up a known pattern in column 0
 	game.state.SelectedColumn = 0
@@ -514,16 +554,16 @@ func TestMoveColumnUp(t *testing.T) {
 	game.state.Grid[2][0] = Wall
 	
 	// Store original pattern for comparison
-	originalPattern := make([]CellType, GridHeight)
-	for i := 0; i < GridHeight; i++ {
+	originalPattern := make([]CellType, cfg.Height)
+	for i := 0; i < cfg.Height; i++ {
 		originalPattern[i] = game.state.Grid[i][0]
 	}
 	
-	game.ProcessAction(ActionMoveColumnUp)
+	game.moveColumnUp()
 	state := game.GetState()
 	
 	// Check that each cell moved up by one position (with wraparound)
-	for row := 0; row < GridHeight-1; row++ {
+	for row := 0; row < cfg.Height-1; row++ {
 		expected := originalPattern[row+1]
 		actual := state.Grid[row][0]
 		if actual != expected {
@@ -532,14 +572,15 @@ func TestMoveColumnUp(t *testing.T) {
 	}
 	
 	// Check wraparound: bottom should be what was originally at top
-	if state.Grid[GridHeight-1][0] != originalPattern[0] {
+	if state.Grid[cfg.Height-1][0] != originalPattern[0] {
 		t.Errorf("After moving up, bottom cell should be %v, got %v", 
-			originalPattern[0], state.Grid[GridHeight-1][0])
+			originalPattern[0], state.Grid[cfg.Height-1][0])
 	}
 }
 
 func TestMoveColumnDown(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	
 	// Set up a known pattern in column 0
 	game.state.SelectedColumn = 0
@@ -548,16 +589,16 @@ func TestMoveColumnDown(t *testing.T) {
 	game.state.Grid[2][0] = Wall
 	
 	// Store original pattern for comparison
-	originalPattern := make([]CellType, GridHeight)
-	for i := 0; i < GridHeight; i++ {
+	originalPattern := make([]CellType, cfg.Height)
+	for i := 0; i < cfg.Height; i++ {
 		originalPattern[i] = game.state.Grid[i][0]
 	}
 	
-	game.ProcessAction(ActionMoveColumnDown)
+	game.moveColumnDown()
 	state := game.GetState()
 	
 	// Check that each cell moved down by one position (with wraparound)
-	for row := 1; row < GridHeight; row++ {
+	for row := 1; row < cfg.Height; row++ {
 		expected := originalPattern[row-1]
 		actual := state.Grid[row][0]
 		if actual != expected {
@@ -566,60 +607,78 @@ func TestMoveColumnDown(t *testing.T) {
 	}
 	
 	// Check wraparound: top should be what was originally at bottom
-	if state.Grid[0][0] != originalPattern[GridHeight-1] {
+	if state.Grid[0][0] != originalPattern[cfg.Height-1] {
 		t.Errorf("After moving down, top cell should be %v, got %v", 
-			originalPattern[GridHeight-1], state.Grid[0][0])
+			originalPattern[cfg.Height-1], state.Grid[0][0])
 	}
 }
 
 func TestMoveSelection(t *testing.T) {
-	game := NewGame()
-	game.state.SelectedColumn = 5
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	clearBoard(game)
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: 12, Col: 2}, Player: Red},
+		{Position: Position{Row: 12, Col: 6}, Player: Red},
+		{Position: Position{Row: 12, Col: 10}, Player: Blue},
+	}
+	game.state.CurrentPlayer = Red
+	game.state.SelectedColumn = 2
 	
-	// Test moving right
+	// The cursor skips columns the player can't move
 	game.ProcessAction(ActionMoveRight)
 	if game.GetState().SelectedColumn != 6 {
 		t.Errorf("After moving right, selected column should be 6, got %d", game.GetState().SelectedColumn)
 	}
-	
-	// Test moving left
 	game.ProcessAction(ActionMoveLeft)
-	if game.GetState().SelectedColumn != 5 {
-		t.Errorf("After moving left, selected column should be 5, got %d", game.GetState().SelectedColumn)
+	if game.GetState().SelectedColumn != 2 {
+		t.Errorf("After moving left, selected column should be 2, got %d", game.GetState().SelectedColumn)
 	}
 }
 
 func TestMoveSelectionBounds(t *testing.T) {
-	// Test left boundary
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	clearBoard(game)
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: 12, Col: 0}, Player: Red},
+		{Position: Position{Row: 12, Col: 4}, Player: Red},
+	}
+	game.state.CurrentPlayer = Red
+	
+	// Past the edge the cursor wraps around to the player's column at the other end
 	game.state.SelectedColumn = 0
 	game.ProcessAction(ActionMoveLeft)
+	if game.GetState().SelectedColumn != 4 {
+		t.Errorf("Moving left from the leftmost valid column should wrap to 4, got %d", game.GetState().SelectedColumn)
+	}
+	game.ProcessAction(ActionMoveRight)
 	if game.GetState().SelectedColumn != 0 {
-		t.Errorf("Selection should not move beyond left boundary, got %d", game.GetState().SelectedColumn)
+		t.Errorf("Moving right from the rightmost valid column should wrap to 0, got %d", game.GetState().SelectedColumn)
 	}
 	
-	// Test right boundary
-	game.state.SelectedColumn = GridWidth - 1
-	game.ProcessAction(ActionMoveRight)
-	if game.GetState().SelectedColumn != GridWidth-1 {
-		t.Errorf("Selection should not move beyond right boundary, got %d", game.GetState().SelectedColumn)
+	// A cursor off the player's columns jumps to the nearest one
+	game.state.SelectedColumn = 3
+	game.moveToValidColumn()
+	if game.GetState().SelectedColumn != 4 {
+		t.Errorf("Nearest valid column to 3 should be 4, got %d", game.GetState().SelectedColumn)
 	}
-}
-
-func TestQuitAction(t *testing.T) {
-	game := NewGame()
-	if game.IsGameOver() {
-		t.Error("Game should not be over initially")
+	game.state.SelectedColumn = game.state.Config.Width - 1
+	game.moveToValidColumn()
+	if game.GetState().SelectedColumn != 4 {
+		t.Errorf("Nearest valid column to the right edge should be 4, got %d", game.GetState().SelectedColumn)
 	}
 	
-	game.ProcessAction(ActionQuit)
-	if !game.IsGameOver() {
-		t.Error("Game should be over after quit action")
+	// With no mice to move the cursor stays put
+	game.state.Mice = nil
+	game.state.SelectedColumn = 7
+	game.ProcessAction(ActionMoveRight)
+	game.moveToValidColumn()
+	if game.GetState().SelectedColumn != 7 {
+		t.Errorf("Selection should stay at 7 without valid columns, got %d", game.GetState().SelectedColumn)
 	}
 }
 
 func TestActionOnGameOver(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
 	game.ProcessAction(ActionQuit) // End the game
 	
 	originalState := game.GetState()
@@ -636,34 +695,16 @@ func TestActionOnGameOver(t *testing.T) {
 	}
 }
 
-func TestReset(t *testing.T) {
-	game := NewGame()
-	
-	// Make some changes
-	game.ProcessAction(ActionMoveRight)
-	game.ProcessAction(ActionQuit)
-	
-	// Reset the game
-	game.Reset()
-	state := game.GetState()
-	
-	if state.GameOver {
-		t.Error("Game should not be over after reset")
-	}
-	
-	if state.SelectedColumn != GridWidth/2 {
-		t.Errorf("Selected column should be reset to %d, got %d", GridWidth/2, state.SelectedColumn)
-	}
-}
-
 func TestWallCountPreservation(t *testing.T) {
-	game := NewGame()
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
 	state := game.GetState()
+	col := state.SelectedColumn
 	
 	// Count walls in selected column before moving
 	originalWallCount := 0
-	for row := 0; row < GridHeight; row++ {
-		if state.Grid[row][state.SelectedColumn] == Wall {
+	for row := 0; row < cfg.Height; row++ {
+		if state.Grid[row][col] == Wall {
 			originalWallCount++
 		}
 	}
@@ -672,8 +713,8 @@ func TestWallCountPreservation(t *testing.T) {
 	game.ProcessAction(ActionMoveColumnUp)
 	newState := game.GetState()
 	newWallCount := 0
-	for row := 0; row < GridHeight; row++ {
-		if newState.Grid[row][newState.SelectedColumn] == Wall {
+	for row := 0; row < cfg.Height; row++ {
+		if newState.Grid[row][col] == Wall {
 			newWallCount++
 		}
 	}
@@ -682,4 +723,539 @@ func TestWallCountPreservation(t *testing.T) {
 		t.Errorf("Wall count should be preserved after move: expected %d, got %d", 
 			originalWallCount, newWallCount)
 	}
-}
\ No newline at end of file
+}
+
+// gridsEqual checks if two grids have the same size and cells
+func gridsEqual(a, b [][]CellType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for row := range a {
+		if len(a[row]) != len(b[row]) {
+			return false
+		}
+		for col := range a[row] {
+			if a[row][col] != b[row][col] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// clearBoard empties the grid and removes all mice so tests can build a known layout
+func clearBoard(game *MicemenGame) {
+	cfg := game.state.Config
+	for row := 0; row < cfg.Height; row++ {
+		for col := 0; col < cfg.Width; col++ {
+			game.state.Grid[row][col] = Empty
+		}
+	}
+	game.state.Mice = nil
+}
+
+func TestApplyGravity(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
+	clearBoard(game)
+	
+	// Wall in the middle of column 2 with a floating stack of two mice above it
+	game.state.Grid[8][2] = Wall
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: 1, Col: 2}, Player: Red},
+		{Position: Position{Row: 3, Col: 2}, Player: Red},
+		{Position: Position{Row: 0, Col: 4}, Player: Blue}, // Nothing below: falls to the floor
+	}
+	
+	game.applyGravity()
+	
+	expected := []Position{
+		{Row: 6, Col: 2},
+		{Row: 7, Col: 2},
+		{Row: cfg.Height - 1, Col: 4},
+	}
+	for i, pos := range expected {
+		if game.state.Mice[i].Position != pos {
+			t.Errorf("Mouse %d should land at %v, got %v", i, pos, game.state.Mice[i].Position)
+		}
+	}
+}
+
+func TestGravityAfterColumnShift(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
+	clearBoard(game)
+	
+	// Mouse resting on the floor wraps to the top when its column moves down
+	game.state.Grid[cfg.Height-1][4] = Wall // Keeps the mouse from walking off after landing
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: cfg.Height - 1, Col: 3}, Player: Red},
+		{Position: Position{Row: cfg.Height - 1, Col: 15}, Player: Blue},
+	}
+	game.state.CurrentPlayer = Red
+	game.state.SelectedColumn = 3
+	
+	game.ProcessAction(ActionMoveColumnDown)
+	
+	mice := game.GetMiceAt(Position{Row: cfg.Height - 1, Col: 3})
+	if len(mice) != 1 {
+		t.Errorf("Mouse should fall back to the floor after wrapping, mice: %v", game.state.Mice)
+	}
+}
+
+func TestMiceStaySupportedAfterTurns(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	
+	for turn := 0; turn < 50 && !game.IsGameOver(); turn++ {
+		if turn%2 == 0 {
+			game.ProcessAction(ActionMoveColumnUp)
+		} else {
+			game.ProcessAction(ActionMoveColumnDown)
+		}
+		game.ProcessAction(ActionMoveRight)
+		
+		for _, mouse := range game.GetState().Mice {
+			if !game.isValidMousePosition(mouse.Position) {
+				t.Fatalf("Turn %d: mouse at row=%d, col=%d lacks proper support",
+					turn, mouse.Position.Row, mouse.Position.Col)
+			}
+		}
+	}
+}
+
+func TestMiceWalkTowardOpponent(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
+	clearBoard(game)
+	
+	// Red walks right along the floor, steps down off a ledge and stops at a wall
+	game.state.Grid[cfg.Height-1][1] = Wall
+	game.state.Grid[cfg.Height-1][2] = Wall
+	game.state.Grid[cfg.Height-1][6] = Wall
+	// Blue walks left until it bumps into the Red mouse
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: cfg.Height - 2, Col: 1}, Player: Red},
+		{Position: Position{Row: cfg.Height - 1, Col: 12}, Player: Blue},
+	}
+	
+	game.resolveMovement(Red)
+	
+	if pos := game.state.Mice[0].Position; pos != (Position{Row: cfg.Height - 1, Col: 5}) {
+		t.Errorf("Red mouse should stop in front of the wall at column 5 on the floor, got %v", pos)
+	}
+	if pos := game.state.Mice[1].Position; pos != (Position{Row: cfg.Height - 1, Col: 7}) {
+		t.Errorf("Blue mouse should stop against the wall at column 7, got %v", pos)
+	}
+}
+
+func TestWalkOrderFavoursMover(t *testing.T) {
+	// Red and Blue race for the same empty cell in column 5
+	setup := func() *MicemenGame {
+		game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
+		clearBoard(game)
+		game.state.Mice = []Mouse{
+			{Position: Position{Row: cfg.Height - 1, Col: 4}, Player: Red},
+			{Position: Position{Row: cfg.Height - 1, Col: 6}, Player: Blue},
+		}
+		return game
+	}
+	
+	game := setup()
+	game.resolveMovement(Red)
+	if pos := game.state.Mice[0].Position; pos.Col != 5 {
+		t.Errorf("Red moved last, so its mouse should take column 5, got %v", pos)
+	}
+	
+	game = setup()
+	game.resolveMovement(Blue)
+	if pos := game.state.Mice[1].Position; pos.Col != 5 {
+		t.Errorf("Blue moved last, so its mouse should take column 5, got %v", pos)
+	}
+}
+
+func TestMouseEscapesOffFarEdge(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
+	clearBoard(game)
+	
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: cfg.Height - 1, Col: cfg.Width - 3}, Player: Red},
+		{Position: Position{Row: 0, Col: 0}, Player: Blue}, // Falls, then walks off the left edge
+	}
+	
+	game.applyGravity()
+	game.resolveMovement(Red)
+	
+	if len(game.state.Mice) != 0 {
+		t.Errorf("Both mice should have left the board, got %v", game.state.Mice)
+	}
+	if game.state.Escaped[Red] != 1 || game.state.Escaped[Blue] != 1 {
+		t.Errorf("Each player should have 1 mouse home, got %v", game.state.Escaped)
+	}
+	if game.IsGameOver() {
+		t.Error("Game should not be over with mice still to bring home")
+	}
+}
+
+func TestWinWhenAllMiceHome(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	cfg := game.state.Config
+	clearBoard(game)
+	
+	// Red has one mouse left to bring home, one column away from the exit
+	game.state.Escaped[Red] = cfg.MicePerPlayer - 1
+	game.state.Grid[cfg.Height-1][cfg.Width-2] = Wall
+	game.state.Mice = []Mouse{
+		{Position: Position{Row: cfg.Height - 2, Col: cfg.Width - 2}, Player: Red},
+		{Position: Position{Row: cfg.Height - 1, Col: cfg.Width - 1}, Player: Blue},
+	}
+	game.state.CurrentPlayer = Red
+	game.state.SelectedColumn = cfg.Width - 2
+	
+	// After the shift the mouse walks over the Blue mouse and off the right edge
+	game.ProcessAction(ActionMoveColumnUp)
+	
+	state := game.GetState()
+	if !state.GameOver {
+		t.Fatalf("Game should be over once all Red mice are home, mice: %v", state.Mice)
+	}
+	if state.Winner != Red {
+		t.Errorf("Winner should be Red, got %v", state.Winner)
+	}
+	if state.Result != ResultAllMiceHome {
+		t.Errorf("Result should be %v, got %v", ResultAllMiceHome, state.Result)
+	}
+}
+
+func TestSeededBoardIsReproducible(t *testing.T) {
+	first := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 42).GetState()
+	second := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 42).GetState()
+	
+	if first.Seed != 42 {
+		t.Errorf("State should record seed 42, got %d", first.Seed)
+	}
+	if !gridsEqual(first.Grid, second.Grid) {
+		t.Error("Same seed should generate the same walls")
+	}
+	if len(first.Mice) != len(second.Mice) {
+		t.Fatalf("Same seed should place the same number of mice, got %d and %d",
+			len(first.Mice), len(second.Mice))
+	}
+	for i := range first.Mice {
+		if first.Mice[i] != second.Mice[i] {
+			t.Errorf("Mouse %d differs between boards: %v vs %v", i, first.Mice[i], second.Mice[i])
+		}
+	}
+	
+	if gridsEqual(NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 43).GetState().Grid, first.Grid) {
+		t.Error("Different seeds should generate different walls")
+	}
+}
+
+func TestResetRestoresSeededBoard(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 7)
+	original := game.GetState()
+	
+	game.ProcessAction(ActionMoveColumnUp)
+	game.Reset()
+	
+	if !gridsEqual(game.GetState().Grid, original.Grid) {
+		t.Error("Reset should regenerate the board from the game's seed")
+	}
+}
+
+func TestCustomBoardSizes(t *testing.T) {
+	for _, cfg := range []Config{NewConfig(11, 9, 0), NewConfig(31, 17, 0)} {
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("%dx%d config should be valid: %v", cfg.Width, cfg.Height, err)
+		}
+		
+		game := NewGameWithSeed(cfg, UniformGenerator{}, 1)
+		state := game.GetState()
+		
+		if len(state.Grid) != cfg.Height || len(state.Grid[0]) != cfg.Width {
+			t.Errorf("Grid should be %dx%d, got %dx%d", cfg.Width, cfg.Height, len(state.Grid[0]), len(state.Grid))
+		}
+		if len(game.GetPlayer(Red).Mice) != cfg.MicePerPlayer || len(game.GetPlayer(Blue).Mice) != cfg.MicePerPlayer {
+			t.Errorf("%dx%d board should have %d mice per player", cfg.Width, cfg.Height, cfg.MicePerPlayer)
+		}
+		for _, mouse := range state.Mice {
+			if !game.isValidMousePosition(mouse.Position) {
+				t.Errorf("%dx%d board: mouse at %v lacks proper support", cfg.Width, cfg.Height, mouse.Position)
+			}
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("Default config should be valid: %v", err)
+	}
+	
+	tooSmall := NewConfig(2, 9, 1)
+	if tooSmall.Validate() == nil {
+		t.Error("Board narrower than 3 columns should be rejected")
+	}
+	
+	tooManyWalls := DefaultConfig()
+	tooManyWalls.MaxWalls = tooManyWalls.Height
+	if tooManyWalls.Validate() == nil {
+		t.Error("Columns filled with walls should be rejected")
+	}
+	
+	tooManyMice := NewConfig(11, 9, 100)
+	if tooManyMice.Validate() == nil {
+		t.Error("More mice than free cells should be rejected")
+	}
+}
+
+func TestGetStateReturnsCopy(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	state := game.GetState()
+	originalCell := game.state.Grid[0][0]
+	
+	state.Grid[0][0] = Wall + 1
+	state.Mice[0].Position.Row = -1
+	
+	if game.state.Grid[0][0] != originalCell {
+		t.Error("Changing a copy's grid should not affect the game")
+	}
+	if game.state.Mice[0].Position.Row == -1 {
+		t.Error("Changing a copy's mice should not affect the game")
+	}
+}
+
+func TestSymmetricBoardIsFair(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Symmetric = true
+	
+	for seed := int64(0); seed < 20; seed++ {
+		game := NewGameWithSeed(cfg, UniformGenerator{}, seed)
+		state := game.GetState()
+		
+		if err := CheckFairness(state); err != nil {
+			t.Fatalf("Seed %d: symmetric board should be fair: %v", seed, err)
+		}
+		if len(game.GetPlayer(Red).Mice) != cfg.MicePerPlayer || len(game.GetPlayer(Blue).Mice) != cfg.MicePerPlayer {
+			t.Errorf("Seed %d: each player should have %d mice", seed, cfg.MicePerPlayer)
+		}
+		for _, mouse := range state.Mice {
+			if !game.isValidMousePosition(mouse.Position) {
+				t.Errorf("Seed %d: mouse at %v lacks proper support", seed, mouse.Position)
+			}
+		}
+	}
+}
+
+func TestCheckFairnessRejectsLopsidedBoards(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Symmetric = true
+	game := NewGameWithSeed(cfg, UniformGenerator{}, 3)
+	
+	// An extra wall on one side breaks the mirror
+	lopsided := game.GetState()
+	col := 0
+	for row := 0; row < cfg.Height; row++ {
+		if lopsided.Grid[row][col] == Empty && len(game.GetMiceAt(Position{Row: row, Col: col})) == 0 {
+			lopsided.Grid[row][col] = Wall
+			break
+		}
+	}
+	if CheckFairness(lopsided) == nil {
+		t.Error("Board with mismatched walls should be unfair")
+	}
+	
+	// A missing mouse leaves Blue a mouse short
+	outnumbered := game.GetState()
+	outnumbered.Mice = outnumbered.Mice[:len(outnumbered.Mice)-1]
+	if CheckFairness(outnumbered) == nil {
+		t.Error("Board with different mouse counts should be unfair")
+	}
+}
+
+func TestBoardGenerators(t *testing.T) {
+	configs := []Config{DefaultConfig(), NewConfig(11, 9, 0), NewConfig(31, 17, 0)}
+	symmetric := DefaultConfig()
+	symmetric.Symmetric = true
+	configs = append(configs, symmetric)
+	
+	for _, gen := range Generators() {
+		for _, cfg := range configs {
+			game := NewGameWithSeed(cfg, gen, 11)
+			state := game.GetState()
+			
+			if state.Generator != gen.Name() {
+				t.Errorf("State should record generator %q, got %q", gen.Name(), state.Generator)
+			}
+			if len(game.GetPlayer(Red).Mice) != cfg.MicePerPlayer || len(game.GetPlayer(Blue).Mice) != cfg.MicePerPlayer {
+				t.Errorf("%s %dx%d: each player should have %d mice", gen.Name(), cfg.Width, cfg.Height, cfg.MicePerPlayer)
+			}
+			for _, mouse := range state.Mice {
+				if !game.isValidMousePosition(mouse.Position) {
+					t.Errorf("%s %dx%d: mouse at %v lacks proper support", gen.Name(), cfg.Width, cfg.Height, mouse.Position)
+				}
+			}
+			if cfg.Symmetric {
+				if err := CheckFairness(state); err != nil {
+					t.Errorf("%s: symmetric board should be fair: %v", gen.Name(), err)
+				}
+			}
+		}
+	}
+}
+
+func TestGeneratorByName(t *testing.T) {
+	gen, err := GeneratorByName("caves")
+	if err != nil || gen.Name() != "caves" {
+		t.Errorf("Should find the caves generator, got %v, %v", gen, err)
+	}
+	if _, err := GeneratorByName("volcano"); err == nil {
+		t.Error("Unknown generator name should be an error")
+	}
+}
+
+func TestNewBoardIsAtRest(t *testing.T) {
+	configs := []Config{DefaultConfig(), NewConfig(11, 9, 0), NewConfig(9, 5, 2)}
+	for i := range 3 {
+		mirrored := configs[i]
+		mirrored.Symmetric = true
+		configs = append(configs, mirrored)
+	}
+	generators := append(Generators(), emptyColumnsGenerator{})
+	
+	// Nothing should fall or walk until a column is shifted, whoever resolves first
+	for _, gen := range generators {
+		for _, cfg := range configs {
+			for seed := int64(1); seed <= 10; seed++ {
+				state := NewGameWithSeed(cfg, gen, seed).GetState()
+				for _, mover := range []PlayerColor{Red, Blue} {
+					sim := MicemenGame{state: state.Clone()}
+					sim.applyGravity()
+					sim.resolveMovement(mover)
+					if !statesEqual(sim.state, state) {
+						t.Errorf("%s %dx%d seed %d: the new board shouldn't change when %s's mice resolve first",
+							gen.Name(), cfg.Width, cfg.Height, seed, mover)
+					}
+				}
+			}
+		}
+	}
+}
+
+// hasMouseOf reports whether a player's mouse stands at the cell
+func hasMouseOf(state GameState, player PlayerColor, row, col int) bool {
+	mice := state.MiceAt(Position{Row: row, Col: col})
+	return len(mice) > 0 && mice[0].Player == player
+}
+
+// emptyColumnsGenerator is a custom game mode that lays no walls of its own
+type emptyColumnsGenerator struct{}
+
+func (emptyColumnsGenerator) Name() string { return "empty" }
+
+func (emptyColumnsGenerator) Generate(state *GameState, rng *rand.Rand) {
+	PopulateBoard(state, rng)
+}
+
+func TestCustomBoardGenerator(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), emptyColumnsGenerator{}, 5)
+	state := game.GetState()
+	cfg := state.Config
+	
+	// The only walls are the ones put in front of mice to keep them from walking off
+	for row := 0; row < cfg.Height; row++ {
+		for col := 0; col < cfg.Width; col++ {
+			if state.Grid[row][col] != Wall {
+				continue
+			}
+			if !hasMouseOf(state, Red, row, col-1) && !hasMouseOf(state, Blue, row, col+1) {
+				t.Fatalf("Custom generator should only get walls in front of mice, found one at row %d, col %d", row, col)
+			}
+		}
+	}
+	if len(state.Mice) != cfg.MicePerPlayer*2 {
+		t.Errorf("Should have %d mice, got %d", cfg.MicePerPlayer*2, len(state.Mice))
+	}
+}
+
+// statesEqual checks if two states have the same board, mice and turn
+func statesEqual(a, b GameState) bool {
+	if !gridsEqual(a.Grid, b.Grid) || len(a.Mice) != len(b.Mice) {
+		return false
+	}
+	for i := range a.Mice {
+		if a.Mice[i] != b.Mice[i] {
+			return false
+		}
+	}
+	return a.CurrentPlayer == b.CurrentPlayer && a.SelectedColumn == b.SelectedColumn &&
+		a.Escaped == b.Escaped && a.LastShiftColumn == b.LastShiftColumn
+}
+
+func TestUndoRedo(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 21)
+	
+	if game.Undo() {
+		t.Error("Undo should fail before any move is made")
+	}
+	
+	start := game.GetState()
+	game.ProcessAction(ActionMoveColumnUp)
+	afterFirst := game.GetState()
+	game.ProcessAction(ActionMoveColumnDown)
+	afterSecond := game.GetState()
+	
+	if undo, redo := game.HistoryDepth(); undo != 2 || redo != 0 {
+		t.Errorf("History should be 2 deep with nothing to redo, got %d and %d", undo, redo)
+	}
+	
+	game.ProcessAction(ActionUndo)
+	if !statesEqual(game.GetState(), afterFirst) {
+		t.Error("Undo should restore the state after the first move")
+	}
+	game.ProcessAction(ActionUndo)
+	if !statesEqual(game.GetState(), start) {
+		t.Error("Undoing both moves should restore the starting state")
+	}
+	if undo, redo := game.HistoryDepth(); undo != 0 || redo != 2 {
+		t.Errorf("History should have 2 moves to redo, got %d and %d", undo, redo)
+	}
+	
+	game.ProcessAction(ActionRedo)
+	game.ProcessAction(ActionRedo)
+	if !statesEqual(game.GetState(), afterSecond) {
+		t.Error("Redoing both moves should restore the latest state")
+	}
+	if game.Redo() {
+		t.Error("Redo should fail with nothing left to replay")
+	}
+}
+
+func TestNewMoveClearsRedo(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 22)
+	
+	game.ProcessAction(ActionMoveColumnUp)
+	game.Undo()
+	game.ProcessAction(ActionMoveColumnDown)
+	
+	if undo, redo := game.HistoryDepth(); undo != 1 || redo != 0 {
+		t.Errorf("A new move should abandon redos, got %d to undo and %d to redo", undo, redo)
+	}
+}
+
+func TestUndoDoesNotShareMice(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 23)
+	start := game.GetState()
+	
+	// Later moves rearrange the mice slice in place; the snapshot must not change with it
+	for i := 0; i < 6; i++ {
+		game.ProcessAction(ActionMoveColumnUp)
+	}
+	for game.Undo() {
+	}
+	
+	if !statesEqual(game.GetState(), start) {
+		t.Error("Undoing every move should restore the starting state exactly")
+	}
+}
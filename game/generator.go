@@ -0,0 +1,288 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// BoardGenerator lays out the walls and mice for a new game
+type BoardGenerator interface {
+	// Name identifies the generator, e.g. on the command line
+	Name() string
+	// Generate fills in a state whose grid is empty and sized by its Config.
+	// All randomness must come from rng so boards can be reproduced from a seed.
+	Generate(state *GameState, rng *rand.Rand)
+}
+
+// Generators returns the built-in board generators
+func Generators() []BoardGenerator {
+	return []BoardGenerator{
+		UniformGenerator{},
+		CavesGenerator{},
+		SparseGenerator{},
+		StaircaseGenerator{},
+	}
+}
+
+// GeneratorByName looks up a built-in board generator
+func GeneratorByName(name string) (BoardGenerator, error) {
+	for _, gen := range Generators() {
+		if gen.Name() == name {
+			return gen, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown board generator %q", name)
+}
+
+// UniformGenerator scatters a random number of walls at random rows in each column
+type UniformGenerator struct{}
+
+// Name returns the generator's name
+func (UniformGenerator) Name() string {
+	return "uniform"
+}
+
+// Generate randomly places walls in each column and then the mice
+func (UniformGenerator) Generate(state *GameState, rng *rand.Rand) {
+	cfg := state.Config
+
+	for col := 0; col < cfg.Width; col++ {
+		// Random number of walls for this column
+		numWalls := rng.Intn(cfg.MaxWalls-cfg.MinWalls+1) + cfg.MinWalls
+
+		// Generate random positions for walls
+		positions := make(map[int]bool)
+		for len(positions) < numWalls {
+			pos := rng.Intn(cfg.Height)
+			positions[pos] = true
+		}
+
+		// Place walls at selected positions
+		for row := 0; row < cfg.Height; row++ {
+			if positions[row] {
+				state.Grid[row][col] = Wall
+			} else {
+				state.Grid[row][col] = Empty
+			}
+		}
+	}
+
+	PopulateBoard(state, rng)
+}
+
+// CavesGenerator builds each column from a few contiguous runs of wall, leaving open
+// caverns between them
+type CavesGenerator struct{}
+
+// Name returns the generator's name
+func (CavesGenerator) Name() string {
+	return "caves"
+}
+
+// Generate lays walls in runs of two to four cells and then places the mice
+func (CavesGenerator) Generate(state *GameState, rng *rand.Rand) {
+	cfg := state.Config
+
+	for col := 0; col < cfg.Width; col++ {
+		numWalls := rng.Intn(cfg.MaxWalls-cfg.MinWalls+1) + cfg.MinWalls
+
+		for placed := 0; placed < numWalls; {
+			run := min(2+rng.Intn(3), numWalls-placed)
+			start := rng.Intn(cfg.Height)
+
+			// Runs may merge with earlier ones, so only count the cells that were empty
+			for row := start; row < start+run && row < cfg.Height; row++ {
+				if state.Grid[row][col] == Empty {
+					state.Grid[row][col] = Wall
+					placed++
+				}
+			}
+		}
+	}
+
+	PopulateBoard(state, rng)
+}
+
+// SparseGenerator leaves most of the board open: each column gets between half of MinWalls
+// and MinWalls walls, and always at least one
+type SparseGenerator struct{}
+
+// Name returns the generator's name
+func (SparseGenerator) Name() string {
+	return "sparse"
+}
+
+// Generate scatters a handful of walls in each column and then places the mice
+func (SparseGenerator) Generate(state *GameState, rng *rand.Rand) {
+	cfg := state.Config
+
+	for col := 0; col < cfg.Width; col++ {
+		numWalls := max(cfg.MinWalls/2, 1)
+		numWalls += rng.Intn(max(cfg.MinWalls-numWalls, 0) + 1)
+
+		for _, row := range rng.Perm(cfg.Height)[:numWalls] {
+			state.Grid[row][col] = Wall
+		}
+	}
+
+	PopulateBoard(state, rng)
+}
+
+// StaircaseGenerator stacks walls up from the floor, with the stack height climbing and
+// falling one step per column so mice have ledges to walk down
+type StaircaseGenerator struct{}
+
+// Name returns the generator's name
+func (StaircaseGenerator) Name() string {
+	return "staircase"
+}
+
+// Generate builds the stacks of wall and then places the mice
+func (StaircaseGenerator) Generate(state *GameState, rng *rand.Rand) {
+	cfg := state.Config
+	steps := cfg.MaxWalls - cfg.MinWalls
+
+	// Start the staircase at a random step so boards differ
+	offset := rng.Intn(2*steps + 1)
+
+	for col := 0; col < cfg.Width; col++ {
+		// Zigzag between MinWalls and MaxWalls
+		step := offset + col
+		if steps > 0 {
+			step %= 2 * steps
+			if step > steps {
+				step = 2*steps - step
+			}
+		} else {
+			step = 0
+		}
+
+		for row := cfg.Height - cfg.MinWalls - step; row < cfg.Height; row++ {
+			state.Grid[row][col] = Wall
+		}
+	}
+
+	PopulateBoard(state, rng)
+}
+
+// PopulateBoard finishes a board once its walls are laid: symmetric configs get the left
+// half mirrored onto the right, then each player's mice are placed in their starting
+// columns. Custom generators can call it after laying out their own walls.
+func PopulateBoard(state *GameState, rng *rand.Rand) {
+	cfg := state.Config
+
+	if cfg.Symmetric {
+		mirrorWalls(state)
+	}
+
+	// Place Red player's mice (left columns)
+	placeMiceForPlayer(state, rng, Red, 0, cfg.Player1Columns-1)
+
+	if cfg.Symmetric {
+		// Red's mice may have needed walls in front of them, which Blue gets too
+		mirrorWalls(state)
+		mirrorMice(state)
+		return
+	}
+
+	// Place Blue player's mice (right columns)
+	placeMiceForPlayer(state, rng, Blue, cfg.Width-cfg.Player2Columns, cfg.Width-1)
+}
+
+// mirrorWalls copies the walls of the left half onto the right half. The middle column
+// of an odd-width board is its own mirror image.
+func mirrorWalls(state *GameState) {
+	cfg := state.Config
+	for col := 0; col < cfg.Width/2; col++ {
+		mirrorCol := cfg.Width - 1 - col
+		for row := 0; row < cfg.Height; row++ {
+			state.Grid[row][mirrorCol] = state.Grid[row][col]
+		}
+	}
+}
+
+// mirrorMice gives Blue a mouse at the mirror image of every Red mouse. Mirrors are added
+// in Red's placement order, so a mouse standing on another mouse is still supported.
+func mirrorMice(state *GameState) {
+	redMice := len(state.Mice)
+	for i := 0; i < redMice; i++ {
+		pos := state.Mice[i].Position
+		state.Mice = append(state.Mice, Mouse{
+			Position: Position{Row: pos.Row, Col: state.Config.Width - 1 - pos.Col},
+			Player:   Blue,
+		})
+	}
+}
+
+// placeMiceForPlayer places mice for a specific player in the given column range. The
+// board has to be at rest before the first move, or mice free to walk would set off with
+// the first shift, the mover's mice first. So each mouse goes where a wall or another
+// mouse is in its way if there is such a cell, and gets a wall put in front of it if not.
+func placeMiceForPlayer(state *GameState, rng *rand.Rand, player PlayerColor, startCol, endCol int) {
+	for i := 0; i < state.Config.MicePerPlayer; i++ {
+		// Find a valid position for this mouse
+		pos := findValidMousePosition(state, rng, player, startCol, endCol)
+		if pos == nil {
+			continue
+		}
+		if !blockedAhead(state, player, *pos) {
+			state.Grid[pos.Row][pos.Col+walkDirection(player)] = Wall
+		}
+		state.Mice = append(state.Mice, Mouse{Position: *pos, Player: player})
+	}
+}
+
+// findValidMousePosition picks a valid position for a player's mouse in the given column
+// range, preferring cells where the mouse can't walk. It returns nil if the columns are full.
+func findValidMousePosition(state *GameState, rng *rand.Rand, player PlayerColor, startCol, endCol int) *Position {
+	var open, blocked []Position
+	for col := startCol; col <= endCol; col++ {
+		for _, row := range getValidRowsForMouse(state, col) {
+			pos := Position{Row: row, Col: col}
+			if blockedAhead(state, player, pos) {
+				blocked = append(blocked, pos)
+			} else {
+				open = append(open, pos)
+			}
+		}
+	}
+
+	candidates := blocked
+	if len(candidates) == 0 {
+		candidates = open
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	pos := candidates[rng.Intn(len(candidates))]
+	return &pos
+}
+
+// blockedAhead reports whether a player's mouse at the position would have a wall or another
+// mouse in its way. On a symmetric board a mouse facing its own mirror image is blocked by
+// the mirrored mouse.
+func blockedAhead(state *GameState, player PlayerColor, pos Position) bool {
+	next := Position{Row: pos.Row, Col: pos.Col + walkDirection(player)}
+	if !state.InBounds(next) {
+		return false
+	}
+	if state.Config.Symmetric && next.Col == state.Config.Width-1-pos.Col {
+		return true
+	}
+	return state.Grid[next.Row][next.Col] == Wall || state.hasMouseAt(next)
+}
+
+// getValidRowsForMouse returns all valid rows where a mouse can be placed in the given column
+func getValidRowsForMouse(state *GameState, col int) []int {
+	var validRows []int
+
+	for row := 0; row < state.Config.Height; row++ {
+		pos := Position{Row: row, Col: col}
+		// Skip cells that already hold a mouse so mice never share a cell
+		if state.IsValidMousePosition(pos) && len(state.MiceAt(pos)) == 0 {
+			validRows = append(validRows, row)
+		}
+	}
+
+	return validRows
+}
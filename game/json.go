@@ -0,0 +1,293 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GameState is saved as a JSON object with a schema version, so saves written by older
+// builds keep loading as the state grows:
+//
+//	{
+//	  "version": 1,
+//	  "config": {"width": 7, "height": 3, "minWalls": 1, "maxWalls": 2, ...},
+//	  "generator": "uniform",
+//	  "seed": 42,
+//	  "grid": ["..#.#..", "..#.#..", "###.###"],
+//	  "mice": [{"row": 1, "col": 0, "player": "Red"}, {"row": 1, "col": 6, "player": "Blue"}],
+//	  "currentPlayer": "Red",
+//	  "selectedColumn": 3,
+//	  "escaped": {"red": 1, "blue": 1},
+//	  "result": "in_progress",
+//	  "lastShift": {"column": 2, "direction": "up"}
+//	}
+//
+// The grid uses the same cell characters as map files. "winner" is only present once the
+// game has been won and "lastShift" only once a column has been shifted.
+
+// StateVersion is the schema version written by MarshalJSON
+const StateVersion = 1
+
+// stateMigrations upgrade a saved state one version at a time: the hook for version v
+// rewrites a version v object into a version v+1 object. When the schema changes, bump
+// StateVersion and add the hook that fills in or converts the changed fields.
+var stateMigrations = map[int]func(fields map[string]json.RawMessage) error{}
+
+// stateJSON is the JSON form of a GameState
+type stateJSON struct {
+	Version        int            `json:"version"`
+	Config         Config         `json:"config"`
+	Generator      string         `json:"generator,omitempty"`
+	Seed           int64          `json:"seed"`
+	Grid           []string       `json:"grid"`
+	Mice           []mouseJSON    `json:"mice"`
+	CurrentPlayer  PlayerColor    `json:"currentPlayer"`
+	SelectedColumn int            `json:"selectedColumn"`
+	Escaped        escapedJSON    `json:"escaped"`
+	GameOver       bool           `json:"gameOver,omitempty"`
+	Winner         *PlayerColor   `json:"winner,omitempty"`
+	Result         GameResult     `json:"result"`
+	LastShift      *lastShiftJSON `json:"lastShift,omitempty"`
+}
+
+// mouseJSON is the JSON form of a Mouse
+type mouseJSON struct {
+	Row    int         `json:"row"`
+	Col    int         `json:"col"`
+	Player PlayerColor `json:"player"`
+}
+
+// escapedJSON is the JSON form of the mice each player got home
+type escapedJSON struct {
+	Red  int `json:"red"`
+	Blue int `json:"blue"`
+}
+
+// lastShiftJSON is the JSON form of the previous turn's column shift
+type lastShiftJSON struct {
+	Column    int       `json:"column"`
+	Direction Direction `json:"direction"`
+}
+
+// MarshalJSON encodes the state in the current schema version
+func (s GameState) MarshalJSON() ([]byte, error) {
+	out := stateJSON{
+		Version:        StateVersion,
+		Config:         s.Config,
+		Generator:      s.Generator,
+		Seed:           s.Seed,
+		Grid:           make([]string, len(s.Grid)),
+		Mice:           make([]mouseJSON, len(s.Mice)),
+		CurrentPlayer:  s.CurrentPlayer,
+		SelectedColumn: s.SelectedColumn,
+		Escaped:        escapedJSON{Red: s.Escaped[Red], Blue: s.Escaped[Blue]},
+		GameOver:       s.GameOver,
+		Result:         s.Result,
+	}
+
+	for row, cells := range s.Grid {
+		line := make([]byte, len(cells))
+		for col, cell := range cells {
+			line[col] = mapEmpty
+			if cell == Wall {
+				line[col] = mapWall
+			}
+		}
+		out.Grid[row] = string(line)
+	}
+	for i, mouse := range s.Mice {
+		out.Mice[i] = mouseJSON{Row: mouse.Position.Row, Col: mouse.Position.Col, Player: mouse.Player}
+	}
+	if s.Winner != NoPlayer {
+		winner := s.Winner
+		out.Winner = &winner
+	}
+	if s.LastShiftColumn >= 0 {
+		out.LastShift = &lastShiftJSON{Column: s.LastShiftColumn, Direction: s.LastShiftDirection}
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a state saved in this or any older schema version
+func (s *GameState) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	version := 0
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return fmt.Errorf("invalid state version: %w", err)
+		}
+	}
+	if version < 1 {
+		return fmt.Errorf("state has no schema version")
+	}
+	if version > StateVersion {
+		return fmt.Errorf("state version %d is newer than this build supports (%d)", version, StateVersion)
+	}
+
+	// Bring older saves up to the current schema before decoding them
+	for ; version < StateVersion; version++ {
+		migrate, ok := stateMigrations[version]
+		if !ok {
+			return fmt.Errorf("no migration from state version %d", version)
+		}
+		if err := migrate(fields); err != nil {
+			return fmt.Errorf("migrating state from version %d: %w", version, err)
+		}
+	}
+
+	migrated, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	var in stateJSON
+	if err := json.Unmarshal(migrated, &in); err != nil {
+		return err
+	}
+
+	state := GameState{
+		Config:          in.Config,
+		Generator:       in.Generator,
+		Seed:            in.Seed,
+		SelectedColumn:  in.SelectedColumn,
+		GameOver:        in.GameOver,
+		CurrentPlayer:   in.CurrentPlayer,
+		Escaped:         [2]int{in.Escaped.Red, in.Escaped.Blue},
+		Winner:          NoPlayer,
+		Result:          in.Result,
+		LastShiftColumn: -1,
+	}
+	if in.Winner != nil {
+		state.Winner = *in.Winner
+	}
+	if in.LastShift != nil {
+		state.LastShiftColumn = in.LastShift.Column
+		state.LastShiftDirection = in.LastShift.Direction
+	}
+
+	// The grid has to match the board size in the config
+	cfg := state.Config
+	if cfg.Width < 1 || cfg.Height < 1 {
+		return fmt.Errorf("invalid board size %dx%d", cfg.Width, cfg.Height)
+	}
+	if len(in.Grid) != cfg.Height {
+		return fmt.Errorf("grid has %d rows, expected %d", len(in.Grid), cfg.Height)
+	}
+	state.Grid = newGrid(cfg.Width, cfg.Height)
+	for row, line := range in.Grid {
+		if len(line) != cfg.Width {
+			return fmt.Errorf("grid row %d is %d cells wide, expected %d", row, len(line), cfg.Width)
+		}
+		for col, ch := range []byte(line) {
+			switch ch {
+			case mapWall:
+				state.Grid[row][col] = Wall
+			case mapEmpty:
+			default:
+				return fmt.Errorf("grid row %d has unexpected character %q", row, ch)
+			}
+		}
+	}
+
+	state.Mice = make([]Mouse, len(in.Mice))
+	for i, mouse := range in.Mice {
+		pos := Position{Row: mouse.Row, Col: mouse.Col}
+		if !state.InBounds(pos) {
+			return fmt.Errorf("mouse %d at row %d, column %d is off the board", i, pos.Row, pos.Col)
+		}
+		if mouse.Player != Red && mouse.Player != Blue {
+			return fmt.Errorf("mouse %d has no player", i)
+		}
+		state.Mice[i] = Mouse{Position: pos, Player: mouse.Player}
+	}
+
+	// Mice have to stand on something in a cell of their own, like they would after a turn
+	occupied := make(map[Position]bool, len(state.Mice))
+	for i, mouse := range state.Mice {
+		pos := mouse.Position
+		if occupied[pos] {
+			return fmt.Errorf("mouse %d at row %d, column %d shares its cell with another mouse", i, pos.Row, pos.Col)
+		}
+		occupied[pos] = true
+		if !state.IsValidMousePosition(pos) {
+			return fmt.Errorf("mouse %d at row %d, column %d is inside a wall or floating", i, pos.Row, pos.Col)
+		}
+	}
+	if state.CurrentPlayer != Red && state.CurrentPlayer != Blue {
+		return fmt.Errorf("current player must be Red or Blue, got %s", state.CurrentPlayer)
+	}
+	state.Hash = state.ComputeHash()
+
+	*s = state
+	return nil
+}
+
+// MarshalText encodes a player color by name
+func (p PlayerColor) MarshalText() ([]byte, error) {
+	if p != Red && p != Blue && p != NoPlayer {
+		return nil, fmt.Errorf("unknown player color %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a player color name
+func (p *PlayerColor) UnmarshalText(text []byte) error {
+	if strings.EqualFold(string(text), NoPlayer.String()) {
+		*p = NoPlayer
+		return nil
+	}
+	player, err := ParsePlayerColor(string(text))
+	if err != nil {
+		return err
+	}
+	*p = player
+	return nil
+}
+
+// resultNames are the names game results are saved under
+var resultNames = map[GameResult]string{
+	ResultInProgress:  "in_progress",
+	ResultAllMiceHome: "all_mice_home",
+	ResultQuit:        "quit",
+}
+
+// MarshalText encodes a game result by name
+func (r GameResult) MarshalText() ([]byte, error) {
+	name, ok := resultNames[r]
+	if !ok {
+		return nil, fmt.Errorf("unknown game result %d", int(r))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a game result name
+func (r *GameResult) UnmarshalText(text []byte) error {
+	for result, name := range resultNames {
+		if name == string(text) {
+			*r = result
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown game result %q", text)
+}
+
+// MarshalText encodes a direction by name
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(d.String())), nil
+}
+
+// UnmarshalText decodes a direction name
+func (d *Direction) UnmarshalText(text []byte) error {
+	for _, dir := range []Direction{DirectionNone, DirectionUp, DirectionDown} {
+		if strings.EqualFold(string(text), dir.String()) {
+			*d = dir
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown direction %q", text)
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStateJSONRoundTrip(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), CavesGenerator{}, 42)
+	setupLockedColumnBoard(game)
+	if err := game.ApplyMove(Move{Column: 5, Direction: DirectionUp}); err != nil {
+		t.Fatalf("Red should be able to move column index 5: %v", err)
+	}
+	game.state.Escaped = [2]int{3, 1}
+	
+	data, err := json.Marshal(game.GetState())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	
+	var loaded GameState
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !statesEqual(game.GetState(), loaded) {
+		t.Errorf("State should survive a JSON round trip, got %+v", loaded)
+	}
+	if loaded.Generator != "caves" || loaded.Seed != 42 || loaded.Escaped != [2]int{3, 1} {
+		t.Errorf("Board metadata should survive a JSON round trip, got %q seed %d escaped %v",
+			loaded.Generator, loaded.Seed, loaded.Escaped)
+	}
+	
+	// Colors, results and directions are saved by name
+	for _, want := range []string{`"version":1`, `"currentPlayer":"Blue"`, `"player":"Red"`,
+		`"result":"in_progress"`, `"direction":"up"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON should contain %s, got %s", want, data)
+		}
+	}
+	if strings.Contains(string(data), `"winner"`) {
+		t.Errorf("JSON shouldn't have a winner while the game is in progress, got %s", data)
+	}
+}
+
+func TestStateJSONFinishedGame(t *testing.T) {
+	state := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 7).GetState()
+	state.GameOver = true
+	state.Winner = Blue
+	state.Result = ResultAllMiceHome
+	
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var loaded GameState
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !loaded.GameOver || loaded.Winner != Blue || loaded.Result != ResultAllMiceHome {
+		t.Errorf("A won game should load as won by Blue, got %+v", loaded)
+	}
+	if loaded.LastShiftColumn != -1 {
+		t.Errorf("A game with no moves should have no last shift, got column %d", loaded.LastShiftColumn)
+	}
+}
+
+func TestStateJSONErrors(t *testing.T) {
+	valid := `"config": {"width": 3, "height": 2, "micePerPlayer": 1},
+		"grid": ["...", "#.#"], "mice": [{"row": 0, "col": 0, "player": "Red"}],
+		"currentPlayer": "Red", "result": "in_progress"`
+	
+	var state GameState
+	if err := json.Unmarshal([]byte(`{"version": 1, `+valid+`}`), &state); err != nil {
+		t.Fatalf("Valid state should load: %v", err)
+	}
+	
+	// A mouse standing on another mouse is supported even though mice are listed top first
+	stacked := `{"version": 1, "config": {"width": 3, "height": 2, "micePerPlayer": 1},
+		"grid": ["...", "..."], "mice": [{"row": 0, "col": 1, "player": "Red"}, {"row": 1, "col": 1, "player": "Blue"}],
+		"currentPlayer": "Blue", "result": "in_progress"}`
+	if err := json.Unmarshal([]byte(stacked), &state); err != nil {
+		t.Errorf("A mouse on top of another mouse should load: %v", err)
+	}
+	
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing version", `{` + valid + `}`},
+		{"newer version", `{"version": 99, ` + valid + `}`},
+		{"short grid", `{"version": 1, "config": {"width": 3, "height": 3}, "grid": ["...", "..."]}`},
+		{"narrow row", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", ".."]}`},
+		{"bad cell", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", ".x."]}`},
+		{"mouse off board", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "..."],
+			"mice": [{"row": 2, "col": 0, "player": "Red"}]}`},
+		{"unknown color", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "..."],
+			"currentPlayer": "Green"}`},
+		{"unknown result", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "..."],
+			"result": "draw"}`},
+		{"no player to move", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "#.#"],
+			"mice": [{"row": 0, "col": 0, "player": "Red"}], "currentPlayer": "None"}`},
+		{"floating mouse", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "#.#"],
+			"mice": [{"row": 0, "col": 1, "player": "Red"}], "currentPlayer": "Red"}`},
+		{"mouse in wall", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "#.#"],
+			"mice": [{"row": 1, "col": 0, "player": "Red"}], "currentPlayer": "Red"}`},
+		{"shared cell", `{"version": 1, "config": {"width": 3, "height": 2}, "grid": ["...", "#.#"],
+			"mice": [{"row": 0, "col": 0, "player": "Red"}, {"row": 0, "col": 0, "player": "Blue"}], "currentPlayer": "Red"}`},
+	}
+	
+	for _, tt := range tests {
+		if err := json.Unmarshal([]byte(tt.json), &state); err == nil {
+			t.Errorf("Unmarshal should fail for %s", tt.name)
+		}
+	}
+}
+
+func TestNewGameFromState(t *testing.T) {
+	original := NewGameWithSeed(DefaultConfig(), StaircaseGenerator{}, 11)
+	setupLockedColumnBoard(original)
+	if err := original.ApplyMove(Move{Column: 5, Direction: DirectionUp}); err != nil {
+		t.Fatalf("Red should be able to move column index 5: %v", err)
+	}
+	
+	data, err := json.Marshal(original.GetState())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var saved GameState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	
+	game := NewGameFromState(saved)
+	if !statesEqual(original.GetState(), game.GetState()) {
+		t.Errorf("Game should start from the saved state, got %+v", game.GetState())
+	}
+	if state := game.GetState(); state.Generator != "staircase" || state.Seed != 11 {
+		t.Errorf("Game should keep the saved board's generator and seed, got %q seed %d", state.Generator, state.Seed)
+	}
+	
+	// The column Red just shifted is still locked for Blue
+	if err := game.ApplyMove(Move{Column: 5, Direction: DirectionDown}); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Column index 5 should stay locked after loading, got %v", err)
+	}
+	if err := game.ApplyMove(Move{Column: 10, Direction: DirectionDown}); err != nil {
+		t.Fatalf("Blue should be able to move column index 10: %v", err)
+	}
+	
+	game.Reset()
+	if !statesEqual(original.GetState(), game.GetState()) {
+		t.Errorf("Reset should go back to the saved state, got %+v", game.GetState())
+	}
+}
+
+func TestMoveJSON(t *testing.T) {
+	moves := []Move{{Column: 6, Direction: DirectionUp}, {Column: 11, Direction: DirectionDown}}
+	data, err := json.Marshal(moves)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `["7U","12D"]` {
+		t.Errorf("Moves should be saved in notation, got %s", data)
+	}
+	
+	var loaded []Move
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0] != moves[0] || loaded[1] != moves[1] {
+		t.Errorf("Moves should survive a JSON round trip, got %v", loaded)
+	}
+	
+	if _, err := json.Marshal(Move{Column: 3}); err == nil {
+		t.Errorf("A move without a direction shouldn't be saved")
+	}
+}
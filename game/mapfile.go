@@ -0,0 +1,308 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Map files describe a hand-made board as plain text:
+//
+//	micemen-map 1
+//	name: Two mice
+//	turn: Red
+//	mice: 2
+//	home: 1 1
+//
+//	..#.#..
+//	R.#.#.B
+//	###.###
+//
+// The first line identifies the format. Header lines of the form "key: value" follow,
+// then a blank line and one line per board row: '#' is a wall, '.' is empty, and 'R' and
+// 'B' are Red and Blue mice standing in an empty cell. Every header is optional: "turn"
+// defaults to Red, "mice" to the number of mice each side has on the board and "home" to
+// no mice home yet.
+
+// mapMagic is the first line of every map file
+const mapMagic = "micemen-map 1"
+
+// Cell characters used in map files
+const (
+	mapWall  = '#'
+	mapEmpty = '.'
+	mapRed   = 'R'
+	mapBlue  = 'B'
+)
+
+// Map is a hand-made board loaded from a map file
+type Map struct {
+	Name   string
+	Author string
+	State  GameState
+}
+
+// MapError reports a problem in a map file. Col is 0 when the whole line is at fault.
+type MapError struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+// Error returns the error message with the position it refers to
+func (e *MapError) Error() string {
+	if e.Col > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// mapErrorf builds a MapError for the given position
+func mapErrorf(line, col int, format string, args ...any) *MapError {
+	return &MapError{Line: line, Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// LoadMap reads a map file from disk
+func LoadMap(path string) (*Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := ReadMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ReadMap parses and validates a map
+func ReadMap(r io.Reader) (*Map, error) {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+
+	// Magic line
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, mapErrorf(1, 0, "empty map file")
+	}
+	lineNo++
+	if strings.TrimSpace(scanner.Text()) != mapMagic {
+		return nil, mapErrorf(lineNo, 0, "expected %q", mapMagic)
+	}
+
+	m := &Map{}
+	state := GameState{
+		CurrentPlayer:   Red,
+		Winner:          NoPlayer,
+		Result:          ResultInProgress,
+		LastShiftColumn: -1,
+	}
+	micePerPlayer := 0
+
+	// Header lines up to the first blank line
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, mapErrorf(lineNo, 0, "expected \"key: value\" header, got %q", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "name":
+			m.Name = value
+		case "author":
+			m.Author = value
+		case "turn":
+			player, err := ParsePlayerColor(value)
+			if err != nil {
+				return nil, mapErrorf(lineNo, 0, "%v", err)
+			}
+			state.CurrentPlayer = player
+		case "mice":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, mapErrorf(lineNo, 0, "mice must be a positive number, got %q", value)
+			}
+			micePerPlayer = n
+		case "home":
+			var red, blue int
+			if _, err := fmt.Sscanf(value, "%d %d", &red, &blue); err != nil || red < 0 || blue < 0 {
+				return nil, mapErrorf(lineNo, 0, "home must be two counts for Red and Blue, got %q", value)
+			}
+			state.Escaped = [2]int{red, blue}
+		default:
+			return nil, mapErrorf(lineNo, 0, "unknown header %q", key)
+		}
+	}
+
+	// Board rows, skipping blank lines before and after the board
+	var rows []string
+	var rowLines []int
+	blankLine := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" {
+			if len(rows) > 0 && blankLine == 0 {
+				blankLine = lineNo
+			}
+			continue
+		}
+		if blankLine > 0 {
+			return nil, mapErrorf(blankLine, 0, "blank line inside the board")
+		}
+		if len(rows) > 0 && len(line) != len(rows[0]) {
+			return nil, mapErrorf(lineNo, 0, "row is %d cells wide, expected %d", len(line), len(rows[0]))
+		}
+		rows = append(rows, line)
+		rowLines = append(rowLines, lineNo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(rows) < 2 || len(rows[0]) < 3 {
+		return nil, mapErrorf(lineNo, 0, "board must be at least 3x2")
+	}
+
+	width, height := len(rows[0]), len(rows)
+	mouseLines := make(map[Position]int)
+	state.Grid = newGrid(width, height)
+	for row, line := range rows {
+		for col, ch := range []byte(line) {
+			pos := Position{Row: row, Col: col}
+			switch ch {
+			case mapWall:
+				state.Grid[row][col] = Wall
+			case mapEmpty:
+			case mapRed, mapBlue:
+				player := Red
+				if ch == mapBlue {
+					player = Blue
+				}
+				state.Mice = append(state.Mice, Mouse{Position: pos, Player: player})
+				mouseLines[pos] = rowLines[row]
+			default:
+				return nil, mapErrorf(rowLines[row], col+1, "unexpected character %q", ch)
+			}
+		}
+	}
+
+	// Both sides must have the same number of mice, counting the ones already home
+	var onBoard [2]int
+	for _, mouse := range state.Mice {
+		onBoard[mouse.Player]++
+	}
+	if micePerPlayer == 0 {
+		micePerPlayer = onBoard[Red] + state.Escaped[Red]
+	}
+	for _, player := range []PlayerColor{Red, Blue} {
+		if total := onBoard[player] + state.Escaped[player]; total != micePerPlayer {
+			return nil, mapErrorf(rowLines[0], 0, "%s has %d mice on the board and %d home, expected %d in total",
+				player, onBoard[player], state.Escaped[player], micePerPlayer)
+		}
+		if onBoard[player] == 0 {
+			return nil, mapErrorf(rowLines[0], 0, "%s has no mice left on the board", player)
+		}
+	}
+
+	state.Config = NewConfig(width, height, micePerPlayer)
+
+	// Mice have to stand on something, like they would after a turn
+	for _, mouse := range state.Mice {
+		if !state.IsValidMousePosition(mouse.Position) {
+			return nil, mapErrorf(mouseLines[mouse.Position], mouse.Position.Col+1,
+				"%s mouse is floating with nothing below it", mouse.Player)
+		}
+	}
+
+	state.Hash = state.ComputeHash()
+	m.State = state
+	return m, nil
+}
+
+// SaveMap writes a map file to disk
+func SaveMap(path string, m *Map) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteMap(f, m); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteMap writes a board in the map file format
+func WriteMap(w io.Writer, m *Map) error {
+	state := m.State
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, mapMagic)
+	if m.Name != "" {
+		fmt.Fprintf(bw, "name: %s\n", m.Name)
+	}
+	if m.Author != "" {
+		fmt.Fprintf(bw, "author: %s\n", m.Author)
+	}
+	fmt.Fprintf(bw, "turn: %s\n", state.CurrentPlayer)
+	fmt.Fprintf(bw, "mice: %d\n", state.Config.MicePerPlayer)
+	fmt.Fprintf(bw, "home: %d %d\n", state.Escaped[Red], state.Escaped[Blue])
+	fmt.Fprintln(bw)
+
+	for row := range state.Grid {
+		line := make([]byte, len(state.Grid[row]))
+		for col, cell := range state.Grid[row] {
+			line[col] = mapEmpty
+			if cell == Wall {
+				line[col] = mapWall
+			}
+		}
+		for _, mouse := range state.Mice {
+			if mouse.Position.Row == row {
+				line[mouse.Position.Col] = mapRed
+				if mouse.Player == Blue {
+					line[mouse.Position.Col] = mapBlue
+				}
+			}
+		}
+		fmt.Fprintln(bw, string(line))
+	}
+
+	return bw.Flush()
+}
+
+// MapGenerator lays out the board from a map instead of generating one
+type MapGenerator struct {
+	Map *Map
+}
+
+// Name returns the generator's name
+func (MapGenerator) Name() string {
+	return "map"
+}
+
+// Generate copies the map's walls, mice, scores and side to move into the state
+func (m MapGenerator) Generate(state *GameState, rng *rand.Rand) {
+	board := m.Map.State.Clone()
+	state.Grid = board.Grid
+	state.Mice = board.Mice
+	state.Escaped = board.Escaped
+	state.CurrentPlayer = board.CurrentPlayer
+}
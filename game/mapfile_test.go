@@ -0,0 +1,139 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testMap = `micemen-map 1
+name: Tiny
+turn: Blue
+mice: 2
+home: 1 1
+
+..#.#..
+R.#.#.B
+###.###
+`
+
+func TestReadMap(t *testing.T) {
+	m, err := ReadMap(strings.NewReader(testMap))
+	if err != nil {
+		t.Fatalf("Map should load: %v", err)
+	}
+	state := m.State
+	
+	if m.Name != "Tiny" {
+		t.Errorf("Name should be Tiny, got %q", m.Name)
+	}
+	if state.Config.Width != 7 || state.Config.Height != 3 {
+		t.Errorf("Board should be 7x3, got %dx%d", state.Config.Width, state.Config.Height)
+	}
+	if state.CurrentPlayer != Blue {
+		t.Errorf("Blue should be to move, got %v", state.CurrentPlayer)
+	}
+	if state.Config.MicePerPlayer != 2 || state.Escaped != [2]int{1, 1} {
+		t.Errorf("Should have 2 mice per player with 1 each home, got %d and %v",
+			state.Config.MicePerPlayer, state.Escaped)
+	}
+	if state.Grid[0][2] != Wall || state.Grid[2][3] != Empty {
+		t.Error("Walls were not read correctly")
+	}
+	
+	expected := []Mouse{
+		{Position: Position{Row: 1, Col: 0}, Player: Red},
+		{Position: Position{Row: 1, Col: 6}, Player: Blue},
+	}
+	if len(state.Mice) != len(expected) {
+		t.Fatalf("Should have %d mice, got %v", len(expected), state.Mice)
+	}
+	for i, mouse := range expected {
+		if state.Mice[i] != mouse {
+			t.Errorf("Mouse %d should be %v, got %v", i, mouse, state.Mice[i])
+		}
+	}
+}
+
+func TestWriteMapRoundTrip(t *testing.T) {
+	original := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 9).GetState()
+	
+	var buf bytes.Buffer
+	if err := WriteMap(&buf, &Map{Name: "Seed 9", State: original}); err != nil {
+		t.Fatalf("Writing map failed: %v", err)
+	}
+	m, err := ReadMap(&buf)
+	if err != nil {
+		t.Fatalf("Written map should load: %v", err)
+	}
+	
+	if m.Name != "Seed 9" {
+		t.Errorf("Name should survive the round trip, got %q", m.Name)
+	}
+	if !gridsEqual(m.State.Grid, original.Grid) {
+		t.Error("Walls should survive the round trip")
+	}
+	if len(m.State.Mice) != len(original.Mice) {
+		t.Fatalf("Should have %d mice, got %d", len(original.Mice), len(m.State.Mice))
+	}
+	for _, mouse := range original.Mice {
+		mice := m.State.MiceAt(mouse.Position)
+		if len(mice) != 1 || mice[0].Player != mouse.Player {
+			t.Errorf("Mouse at %v should survive the round trip", mouse.Position)
+		}
+	}
+}
+
+func TestReadMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line int
+		col  int
+	}{
+		{"missing magic", "..#\nR.B\n", 1, 0},
+		{"unknown header", "micemen-map 1\ncolour: red\n\n...\nR.B\n", 2, 0},
+		{"bad character", "micemen-map 1\n\n...\nRxB\n", 4, 2},
+		{"ragged row", "micemen-map 1\n\n...\nR.B.\n", 4, 0},
+		{"floating mouse", "micemen-map 1\n\nR.B\n.#.\n...\n", 3, 1},
+		{"uneven mice", "micemen-map 1\n\n...\nRRB\n", 3, 0},
+		{"gap in board", "micemen-map 1\n\n...\n\nR.B\n", 4, 0},
+	}
+	
+	for _, tt := range tests {
+		_, err := ReadMap(strings.NewReader(tt.text))
+		var mapErr *MapError
+		if !errors.As(err, &mapErr) {
+			t.Errorf("%s: expected a MapError, got %v", tt.name, err)
+			continue
+		}
+		if mapErr.Line != tt.line || mapErr.Col != tt.col {
+			t.Errorf("%s: error should point at line %d, column %d, got %v", tt.name, tt.line, tt.col, mapErr)
+		}
+	}
+}
+
+func TestMapGenerator(t *testing.T) {
+	m, err := LoadMap("../maps/duel.map")
+	if err != nil {
+		t.Fatalf("Example map should load: %v", err)
+	}
+	
+	game := NewGameWithSeed(m.State.Config, MapGenerator{Map: m}, 0)
+	state := game.GetState()
+	
+	if !gridsEqual(state.Grid, m.State.Grid) || len(state.Mice) != len(m.State.Mice) {
+		t.Error("Game should start from the map's board")
+	}
+	if !game.canPlayerMoveColumn(state.CurrentPlayer, state.SelectedColumn) {
+		t.Error("Initial selection should be valid on a map board")
+	}
+	
+	// Reset goes back to the map rather than a random board
+	game.ProcessAction(ActionMoveColumnUp)
+	game.Reset()
+	if !gridsEqual(game.GetState().Grid, m.State.Grid) {
+		t.Error("Reset should restore the map's board")
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Errors returned when a move can't be applied
+var (
+	ErrGameOver    = errors.New("game is over")
+	ErrIllegalMove = errors.New("illegal move")
+)
+
+// Move is a single column shift. Its notation is the 1-based column number followed by
+// U or D for the direction, e.g. "7U" or "12D", matching the column numbers on screen.
+type Move struct {
+	Column    int // 0-based column index
+	Direction Direction
+}
+
+// String returns the move in notation, e.g. "7U"
+func (m Move) String() string {
+	dir := "?"
+	switch m.Direction {
+	case DirectionUp:
+		dir = "U"
+	case DirectionDown:
+		dir = "D"
+	}
+	return strconv.Itoa(m.Column+1) + dir
+}
+
+// ParseMove reads a move written in notation. The direction letter may be lower case.
+func ParseMove(s string) (Move, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return Move{}, fmt.Errorf("invalid move %q: expected a column and U or D, e.g. 7U", s)
+	}
+
+	var dir Direction
+	switch s[len(s)-1] {
+	case 'U', 'u':
+		dir = DirectionUp
+	case 'D', 'd':
+		dir = DirectionDown
+	default:
+		return Move{}, fmt.Errorf("invalid move %q: direction must be U or D", s)
+	}
+
+	col, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || col < 1 {
+		return Move{}, fmt.Errorf("invalid move %q: column must be a number from 1", s)
+	}
+
+	return Move{Column: col - 1, Direction: dir}, nil
+}
+
+// MarshalText encodes the move in notation, so moves are saved as e.g. "7U"
+func (m Move) MarshalText() ([]byte, error) {
+	if m.Direction != DirectionUp && m.Direction != DirectionDown {
+		return nil, fmt.Errorf("move %v has no direction", m)
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a move written in notation
+func (m *Move) UnmarshalText(text []byte) error {
+	move, err := ParseMove(string(text))
+	if err != nil {
+		return err
+	}
+	*m = move
+	return nil
+}
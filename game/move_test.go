@@ -0,0 +1,97 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMoveNotation(t *testing.T) {
+	tests := []struct {
+		text string
+		move Move
+	}{
+		{"7U", Move{Column: 6, Direction: DirectionUp}},
+		{"12D", Move{Column: 11, Direction: DirectionDown}},
+		{"1u", Move{Column: 0, Direction: DirectionUp}},
+	}
+	
+	for _, tt := range tests {
+		move, err := ParseMove(tt.text)
+		if err != nil {
+			t.Errorf("ParseMove(%q) failed: %v", tt.text, err)
+			continue
+		}
+		if move != tt.move {
+			t.Errorf("ParseMove(%q) should be %+v, got %+v", tt.text, tt.move, move)
+		}
+	}
+	
+	if s := (Move{Column: 11, Direction: DirectionDown}).String(); s != "12D" {
+		t.Errorf("Move string should be 12D, got %q", s)
+	}
+	
+	for _, bad := range []string{"", "U", "7", "0U", "7X", "xU", "-3D"} {
+		if _, err := ParseMove(bad); err == nil {
+			t.Errorf("ParseMove(%q) should fail", bad)
+		}
+	}
+}
+
+func TestApplyMove(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	setupLockedColumnBoard(game)
+	game.state.SelectedColumn = 3
+	
+	// Applying a move ignores where the cursor is
+	if err := game.ApplyMove(Move{Column: 5, Direction: DirectionUp}); err != nil {
+		t.Fatalf("Red should be able to move column index 5: %v", err)
+	}
+	state := game.GetState()
+	if state.CurrentPlayer != Blue || state.LastShiftColumn != 5 || state.LastShiftDirection != DirectionUp {
+		t.Errorf("Move 6U should be recorded and hand the turn to Blue, got %+v", state)
+	}
+	
+	// Blue has no mice in column index 3, and column index 5 is locked
+	for _, move := range []Move{{Column: 3, Direction: DirectionDown}, {Column: 5, Direction: DirectionDown}} {
+		if err := game.ApplyMove(move); !errors.Is(err, ErrIllegalMove) {
+			t.Errorf("Move %v should be illegal, got %v", move, err)
+		}
+	}
+	if err := game.ApplyMove(Move{Column: 10}); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Move without a direction should be illegal, got %v", err)
+	}
+	
+	game.ProcessAction(ActionQuit)
+	if err := game.ApplyMove(Move{Column: 10, Direction: DirectionUp}); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Moves after the game is over should fail, got %v", err)
+	}
+}
+
+func TestMovesAndInitialState(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 31)
+	start := game.GetState()
+	
+	var played []Move
+	for i := 0; i < 4 && !game.IsGameOver(); i++ {
+		move := Move{Column: game.GetValidColumnsForPlayer(game.GetState().CurrentPlayer)[0], Direction: DirectionDown}
+		if err := game.ApplyMove(move); err != nil {
+			t.Fatalf("Move %v should be legal: %v", move, err)
+		}
+		played = append(played, move)
+	}
+	game.Undo()
+	played = played[:len(played)-1]
+	
+	moves := game.Moves()
+	if len(moves) != len(played) {
+		t.Fatalf("Should have %d moves, got %v", len(played), moves)
+	}
+	for i := range played {
+		if moves[i] != played[i] {
+			t.Errorf("Move %d should be %v, got %v", i, played[i], moves[i])
+		}
+	}
+	if !statesEqual(game.InitialState(), start) {
+		t.Error("Initial state should be the board before the first move")
+	}
+}
@@ -0,0 +1,38 @@
+package game
+
+// LegalMoves returns every move the player to move can make: both directions for each
+// column they may shift, from left to right, except shifting the column the opponent just
+// shifted back again. A finished game has no legal moves.
+func LegalMoves(state GameState) []Move {
+	if state.GameOver {
+		return nil
+	}
+
+	// The rules only read the state here, so it doesn't need copying
+	sim := MicemenGame{state: state}
+	columns := sim.getValidColumnsForPlayer(state.CurrentPlayer)
+
+	moves := make([]Move, 0, 2*len(columns))
+	for _, col := range columns {
+		for _, dir := range []Direction{DirectionUp, DirectionDown} {
+			if m := (Move{Column: col, Direction: dir}); !sim.isMoveLocked(state.CurrentPlayer, m) {
+				moves = append(moves, m)
+			}
+		}
+	}
+	return moves
+}
+
+// Apply returns the state after the player to move makes the move, with the board settled
+// and the turn handed over. The given state is left untouched, so states shared with a
+// live game or a search tree can be passed in directly.
+func Apply(state GameState, m Move) (GameState, error) {
+	sim := MicemenGame{state: state}
+	if err := sim.checkMove(m); err != nil {
+		return GameState{}, err
+	}
+
+	sim.state = state.Clone()
+	sim.playMove(m)
+	return sim.state, nil
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLegalMoves(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	setupLockedColumnBoard(game)
+	
+	moves := LegalMoves(game.GetState())
+	want := []Move{
+		{Column: 3, Direction: DirectionUp}, {Column: 3, Direction: DirectionDown},
+		{Column: 5, Direction: DirectionUp}, {Column: 5, Direction: DirectionDown},
+	}
+	if len(moves) != len(want) {
+		t.Fatalf("Red should have %d legal moves, got %v", len(want), moves)
+	}
+	for i := range want {
+		if moves[i] != want[i] {
+			t.Errorf("Legal move %d should be %v, got %v", i, want[i], moves[i])
+		}
+	}
+	
+	// Blue may not shift the column Red just shifted straight back
+	game.ApplyMove(Move{Column: 5, Direction: DirectionUp})
+	moves = LegalMoves(game.GetState())
+	want = []Move{
+		{Column: 5, Direction: DirectionUp},
+		{Column: 10, Direction: DirectionUp}, {Column: 10, Direction: DirectionDown},
+	}
+	if len(moves) != len(want) {
+		t.Fatalf("Blue should have %d legal moves, got %v", len(want), moves)
+	}
+	for i := range want {
+		if moves[i] != want[i] {
+			t.Errorf("Blue's legal move %d should be %v, got %v", i, want[i], moves[i])
+		}
+	}
+	
+	state := game.GetState()
+	state.GameOver = true
+	if moves := LegalMoves(state); len(moves) != 0 {
+		t.Errorf("A finished game should have no legal moves, got %v", moves)
+	}
+}
+
+func TestApplyMatchesApplyMove(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 9)
+	
+	for turn := 0; turn < 40 && !game.IsGameOver(); turn++ {
+		before := game.GetState()
+		original := before.Clone()
+		move := LegalMoves(before)[turn%len(LegalMoves(before))]
+		
+		after, err := Apply(before, move)
+		if err != nil {
+			t.Fatalf("Apply(%v) failed on turn %d: %v", move, turn, err)
+		}
+		if !statesEqual(before, original) || before.CurrentPlayer != original.CurrentPlayer {
+			t.Fatalf("Apply shouldn't change the state it was given")
+		}
+		
+		if err := game.ApplyMove(move); err != nil {
+			t.Fatalf("ApplyMove(%v) failed on turn %d: %v", move, turn, err)
+		}
+		if !statesEqual(after, game.GetState()) {
+			t.Fatalf("Apply and ApplyMove should agree after %v on turn %d", move, turn)
+		}
+	}
+}
+
+func TestApplySharesNoMemory(t *testing.T) {
+	state := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 9).GetState()
+	next, err := Apply(state, LegalMoves(state)[0])
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	
+	next.Grid[0][0] = Wall
+	next.Mice[0].Position.Row = -1
+	if state.Mice[0].Position.Row == -1 {
+		t.Error("Apply should deep copy the mice")
+	}
+	if len(state.Grid) > 0 && &state.Grid[0][0] == &next.Grid[0][0] {
+		t.Error("Apply should deep copy the grid")
+	}
+}
+
+func TestApplyRejectsIllegalMoves(t *testing.T) {
+	game := NewGame(DefaultConfig(), UniformGenerator{})
+	setupLockedColumnBoard(game)
+	state := game.GetState()
+	
+	if _, err := Apply(state, Move{Column: 10, Direction: DirectionUp}); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("Red moving Blue's column should be illegal, got %v", err)
+	}
+	if _, err := Apply(state, Move{Column: 3}); !errors.Is(err, ErrIllegalMove) {
+		t.Errorf("A move without a direction should be illegal, got %v", err)
+	}
+	
+	state.GameOver = true
+	if _, err := Apply(state, Move{Column: 3, Direction: DirectionUp}); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Moving in a finished game should fail with ErrGameOver, got %v", err)
+	}
+}
+
+func TestApplyIsAllocationLight(t *testing.T) {
+	state := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 3).GetState()
+	move := LegalMoves(state)[0]
+	
+	// Copying the grid and mice plus some bookkeeping, however many mice walk
+	allocs := testing.AllocsPerRun(100, func() {
+		Apply(state, move)
+	})
+	if allocs > 10 {
+		t.Errorf("Apply should only allocate a handful of times, got %.0f allocations", allocs)
+	}
+}
@@ -1,14 +1,9 @@
 package game
 
-// Constants for game configuration
-const (
-	GridWidth      = 19
-	GridHeight     = 13
-	MinWalls       = 5
-	MaxWalls       = 8
-	MicePerPlayer  = 12
-	Player1Columns = 9 // Left-most columns for Player 1
-	Player2Columns = 9 // Right-most columns for Player 2
+import (
+	"fmt"
+	"strings"
+	"time"
 )
 
 // CellType represents what's in a grid cell
@@ -27,6 +22,9 @@ const (
 	Blue
 )
 
+// NoPlayer marks the absence of a player, e.g. when nobody has won yet
+const NoPlayer PlayerColor = -1
+
 // String returns the string representation of a player color
 func (p PlayerColor) String() string {
 	switch p {
@@ -34,6 +32,51 @@ func (p PlayerColor) String() string {
 		return "Red"
 	case Blue:
 		return "Blue"
+	case NoPlayer:
+		return "None"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParsePlayerColor converts a color name such as "Red" or "blue" into a PlayerColor
+func ParsePlayerColor(name string) (PlayerColor, error) {
+	switch strings.ToLower(name) {
+	case "red":
+		return Red, nil
+	case "blue":
+		return Blue, nil
+	default:
+		return NoPlayer, fmt.Errorf("unknown player color %q", name)
+	}
+}
+
+// Opponent returns the other player's color
+func (p PlayerColor) Opponent() PlayerColor {
+	if p == Red {
+		return Blue
+	}
+	return Red
+}
+
+// GameResult describes how a game ended
+type GameResult int
+
+const (
+	ResultInProgress GameResult = iota
+	ResultAllMiceHome
+	ResultQuit
+)
+
+// String returns the string representation of a game result
+func (r GameResult) String() string {
+	switch r {
+	case ResultInProgress:
+		return "In progress"
+	case ResultAllMiceHome:
+		return "All mice home"
+	case ResultQuit:
+		return "Quit"
 	default:
 		return "Unknown"
 	}
@@ -51,6 +94,27 @@ type Mouse struct {
 	Player   PlayerColor
 }
 
+// Direction represents which way a column is shifted
+type Direction int
+
+const (
+	DirectionNone Direction = iota
+	DirectionUp
+	DirectionDown
+)
+
+// String returns the string representation of a direction
+func (d Direction) String() string {
+	switch d {
+	case DirectionUp:
+		return "Up"
+	case DirectionDown:
+		return "Down"
+	default:
+		return "None"
+	}
+}
+
 // Action represents player actions
 type Action int
 
@@ -61,15 +125,129 @@ const (
 	ActionMoveColumnUp
 	ActionMoveColumnDown
 	ActionQuit
+	ActionUndo
+	ActionRedo
+	ActionHint           // Suggest a move for the player to move
+	ActionToggleAnalysis // Show or hide the score of every move
+
+	// Replay viewer controls
+	ActionStepBack
+	ActionStepForward
+	ActionJumpToStart
+	ActionJumpToEnd
+	ActionTogglePlay
 )
 
 // GameState represents the current state of the game
 type GameState struct {
-	Grid           [GridHeight][GridWidth]CellType
+	Config         Config
+	Generator      string       // Name of the BoardGenerator that laid out the board
+	Seed           int64        // Seed the board was generated from
+	Grid           [][]CellType // Indexed [row][col], sized by Config
 	SelectedColumn int
 	GameOver       bool
 	CurrentPlayer  PlayerColor
 	Mice           []Mouse
+	Escaped        [2]int // Mice that made it off the far edge, indexed by PlayerColor
+	Winner         PlayerColor
+	Result         GameResult
+
+	// Column shifted on the previous turn, which the player to move may not touch (-1 if none)
+	LastShiftColumn    int
+	LastShiftDirection Direction
+
+	// Zobrist hash of the position, kept up to date by moves; see ComputeHash
+	Hash uint64
+	keys *zobristKeys
+}
+
+// newGrid allocates an empty grid backed by a single slice
+func newGrid(width, height int) [][]CellType {
+	cells := make([]CellType, width*height)
+	grid := make([][]CellType, height)
+	for row := range grid {
+		grid[row] = cells[row*width : (row+1)*width]
+	}
+	return grid
+}
+
+// InBounds checks if the position lies on the board
+func (s *GameState) InBounds(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < s.Config.Height && pos.Col >= 0 && pos.Col < s.Config.Width
+}
+
+// MiceAt returns all mice at the given position
+func (s *GameState) MiceAt(pos Position) []Mouse {
+	var mice []Mouse
+	for _, mouse := range s.Mice {
+		if mouse.Position.Row == pos.Row && mouse.Position.Col == pos.Col {
+			mice = append(mice, mouse)
+		}
+	}
+	return mice
+}
+
+// IsValidMousePosition checks if a mouse can be placed at the given position
+func (s *GameState) IsValidMousePosition(pos Position) bool {
+	// Check if position is within bounds
+	if !s.InBounds(pos) {
+		return false
+	}
+
+	// A mouse can't stand inside a wall
+	if s.Grid[pos.Row][pos.Col] == Wall {
+		return false
+	}
+
+	// Mouse must be standing on the floor, a wall or another mouse
+	return s.isSupported(pos)
+}
+
+// isSupported checks if the cell below the given position can hold a mouse up
+func (s *GameState) isSupported(pos Position) bool {
+	// Bottom row: the floor of the board holds the mouse
+	if pos.Row == s.Config.Height-1 {
+		return true
+	}
+
+	// Check if there's support below (wall or mouse)
+	belowPos := Position{Row: pos.Row + 1, Col: pos.Col}
+
+	// Check for wall below
+	if s.Grid[belowPos.Row][belowPos.Col] == Wall {
+		return true
+	}
+
+	// Check for mouse below
+	return s.hasMouseAt(belowPos)
+}
+
+// hasMouseAt checks if any mouse is at the given position, without collecting them
+func (s *GameState) hasMouseAt(pos Position) bool {
+	for _, mouse := range s.Mice {
+		if mouse.Position == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a deep copy of the state that shares no memory with the original
+func (s GameState) Clone() GameState {
+	clone := s
+
+	if s.Grid != nil {
+		clone.Grid = newGrid(s.Config.Width, s.Config.Height)
+		for row := range s.Grid {
+			copy(clone.Grid[row], s.Grid[row])
+		}
+	}
+	if s.Mice != nil {
+		clone.Mice = make([]Mouse, len(s.Mice))
+		copy(clone.Mice, s.Mice)
+	}
+
+	return clone
 }
 
 // Player represents a player in the game
@@ -88,6 +266,12 @@ type Game interface {
 	GetMiceAt(pos Position) []Mouse
 	CanPlayerMoveColumn(player PlayerColor, col int) bool
 	GetValidColumnsForPlayer(player PlayerColor) []int
+	ApplyMove(m Move) error
+	Moves() []Move
+	InitialState() GameState
+	Undo() bool
+	Redo() bool
+	HistoryDepth() (undo, redo int)
 }
 
 // Renderer interface for displaying the game
@@ -101,5 +285,7 @@ type Renderer interface {
 type InputHandler interface {
 	Initialize() error
 	GetNextAction() (Action, error)
+	// WaitForAction is like GetNextAction but gives up with ActionNone after the timeout
+	WaitForAction(timeout time.Duration) (Action, error)
 	Close() error
 }
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Positions are identified by a Zobrist hash: every wall, every mouse of each color in
+// each cell, the last shift's column and direction and Blue being to move has its own
+// random key, and the hash is the XOR of the keys of everything in the position. Moves
+// keep the hash up to date by XORing keys in and out as things change, which is far
+// cheaper than rehashing the board. Mice home aren't hashed: each side's total is fixed,
+// so the mice left on the board already say how many made it home.
+
+// zobristKeys holds the random keys for one board size
+type zobristKeys struct {
+	walls      []uint64    // Indexed by cell
+	mice       [2][]uint64 // Indexed by PlayerColor, then cell
+	lastShift  [3][]uint64 // Indexed by Direction, then column
+	blueToMove uint64
+}
+
+// zobristCache shares the keys of each board size between all states
+var zobristCache = struct {
+	sync.Mutex
+	keys map[[2]int]*zobristKeys
+}{keys: make(map[[2]int]*zobristKeys)}
+
+// keysForBoard returns the keys for a board size. They're generated from a fixed seed, so
+// hashes are the same from one run to the next.
+func keysForBoard(width, height int) *zobristKeys {
+	zobristCache.Lock()
+	defer zobristCache.Unlock()
+
+	size := [2]int{width, height}
+	if keys, ok := zobristCache.keys[size]; ok {
+		return keys
+	}
+
+	rng := rand.New(rand.NewSource(int64(width)<<32 | int64(height)))
+	random := func(n int) []uint64 {
+		keys := make([]uint64, n)
+		for i := range keys {
+			keys[i] = rng.Uint64()
+		}
+		return keys
+	}
+
+	cells := width * height
+	keys := &zobristKeys{
+		walls:      random(cells),
+		mice:       [2][]uint64{random(cells), random(cells)},
+		lastShift:  [3][]uint64{random(width), random(width), random(width)},
+		blueToMove: rng.Uint64(),
+	}
+	zobristCache.keys[size] = keys
+	return keys
+}
+
+// hashKeys returns the keys for the state's board size
+func (s *GameState) hashKeys() *zobristKeys {
+	if s.keys == nil {
+		s.keys = keysForBoard(s.Config.Width, s.Config.Height)
+	}
+	return s.keys
+}
+
+// ComputeHash hashes the position from scratch. Hash holds the same value for states
+// kept up to date by moves.
+func (s *GameState) ComputeHash() uint64 {
+	keys := s.hashKeys()
+	width := s.Config.Width
+
+	var hash uint64
+	for row := range s.Grid {
+		for col, cell := range s.Grid[row] {
+			if cell == Wall {
+				hash ^= keys.walls[row*width+col]
+			}
+		}
+	}
+	for _, mouse := range s.Mice {
+		hash ^= keys.mice[mouse.Player][mouse.Position.Row*width+mouse.Position.Col]
+	}
+	hash ^= s.lastShiftKey()
+	if s.CurrentPlayer == Blue {
+		hash ^= keys.blueToMove
+	}
+	return hash
+}
+
+// ColumnWallsHash returns what the walls in a column contribute to Hash, so the column
+// can be left out of a position's identity when nothing can reach it any more
+func (s *GameState) ColumnWallsHash(col int) uint64 {
+	keys := s.hashKeys()
+	var hash uint64
+	for row := range s.Grid {
+		if s.Grid[row][col] == Wall {
+			hash ^= keys.walls[row*s.Config.Width+col]
+		}
+	}
+	return hash
+}
+
+// hashColumnWalls XORs the keys of the walls in a column into the hash. Calling it before
+// and after shifting the column swaps the old walls for the new ones.
+func (s *GameState) hashColumnWalls(col int) {
+	s.Hash ^= s.ColumnWallsHash(col)
+}
+
+// hashMouse XORs the key of a mouse in its current cell into the hash, adding the mouse
+// if it wasn't hashed there and removing it if it was
+func (s *GameState) hashMouse(mouse Mouse) {
+	s.Hash ^= s.hashKeys().mice[mouse.Player][mouse.Position.Row*s.Config.Width+mouse.Position.Col]
+}
+
+// hashLastShift XORs the key of the last shift into the hash
+func (s *GameState) hashLastShift() {
+	s.Hash ^= s.lastShiftKey()
+}
+
+// lastShiftKey returns the key of the last shift, which decides the move that's locked
+// this turn, or 0 before any column has been shifted
+func (s *GameState) lastShiftKey() uint64 {
+	col, dir := s.LastShiftColumn, s.LastShiftDirection
+	if col < 0 || col >= s.Config.Width || dir < DirectionNone || dir > DirectionDown {
+		return 0
+	}
+	return s.hashKeys().lastShift[dir][col]
+}
+
+// hashTurn flips the side to move in the hash
+func (s *GameState) hashTurn() {
+	s.Hash ^= s.hashKeys().blueToMove
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestIncrementalHashMatchesComputed(t *testing.T) {
+	for _, gen := range Generators() {
+		for seed := int64(1); seed <= 5; seed++ {
+			state := NewGameWithSeed(DefaultConfig(), gen, seed).GetState()
+			if state.Hash != state.ComputeHash() {
+				t.Fatalf("%s board %d should start with its computed hash", gen.Name(), seed)
+			}
+			
+			// Shifts, falls, walks and escapes all update the hash along the way
+			rng := rand.New(rand.NewSource(seed))
+			for turn := 0; turn < 300 && !state.GameOver; turn++ {
+				moves := LegalMoves(state)
+				next, err := Apply(state, moves[rng.Intn(len(moves))])
+				if err != nil {
+					t.Fatalf("Apply failed: %v", err)
+				}
+				state = next
+				
+				if state.Hash != state.ComputeHash() {
+					t.Fatalf("%s board %d: incremental hash %x should match computed hash %x after turn %d",
+						gen.Name(), seed, state.Hash, state.ComputeHash(), turn)
+				}
+			}
+		}
+	}
+}
+
+func TestHashFollowsUndoAndRedo(t *testing.T) {
+	game := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 4)
+	start := game.GetState().Hash
+	
+	for i := 0; i < 6; i++ {
+		game.ApplyMove(LegalMoves(game.GetState())[0])
+	}
+	end := game.GetState().Hash
+	if end == start {
+		t.Error("Moves should change the hash")
+	}
+	
+	for game.Undo() {
+	}
+	if game.GetState().Hash != start {
+		t.Error("Undoing every move should bring back the starting hash")
+	}
+	for game.Redo() {
+	}
+	if game.GetState().Hash != end {
+		t.Error("Redoing every move should bring back the final hash")
+	}
+}
+
+func TestHashDistinguishesPositions(t *testing.T) {
+	state := NewGameWithSeed(DefaultConfig(), UniformGenerator{}, 4).GetState()
+	base := state.ComputeHash()
+	
+	blue := state.Clone()
+	blue.CurrentPlayer = Blue
+	if blue.ComputeHash() == base {
+		t.Error("The side to move should change the hash")
+	}
+	
+	locked := state.Clone()
+	locked.LastShiftColumn = 3
+	locked.LastShiftDirection = DirectionUp
+	if locked.ComputeHash() == base {
+		t.Error("The locked column should change the hash")
+	}
+	down := locked.Clone()
+	down.LastShiftDirection = DirectionDown
+	if down.ComputeHash() == locked.ComputeHash() {
+		t.Error("The direction of the last shift should change the hash")
+	}
+	
+	moved := state.Clone()
+	moved.Mice[0].Player = moved.Mice[0].Player.Opponent()
+	if moved.ComputeHash() == base {
+		t.Error("The color of a mouse should change the hash")
+	}
+	
+	// The keys don't depend on the run, so saved states hash the same when loaded
+	data, _ := json.Marshal(state)
+	var loaded GameState
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if loaded.Hash != state.Hash {
+		t.Errorf("Loaded state should have hash %x, got %x", state.Hash, loaded.Hash)
+	}
+}
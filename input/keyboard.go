@@ -1,14 +1,68 @@
 package input
 
 import (
+	"time"
+
 	"micemen/game"
 
 	"github.com/eiannone/keyboard"
 )
 
+// keyReader reads key presses in the background, so callers can stop waiting for one
+// after a timeout
+type keyReader struct {
+	keys <-chan keyboard.KeyEvent
+}
+
+// open starts reading keys
+func (k *keyReader) open() error {
+	if k.keys != nil {
+		return nil
+	}
+
+	keys, err := keyboard.GetKeys(10)
+	if err != nil {
+		return err
+	}
+
+	k.keys = keys
+	return nil
+}
+
+// next waits for a key press. A timeout of 0 waits forever; ok is false if the timeout
+// passed without a key being pressed.
+func (k *keyReader) next(timeout time.Duration) (char rune, key keyboard.Key, ok bool, err error) {
+	if err := k.open(); err != nil {
+		return 0, 0, false, err
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout)
+	}
+
+	select {
+	case event := <-k.keys:
+		if event.Err != nil {
+			return 0, 0, false, event.Err
+		}
+		return event.Rune, event.Key, true, nil
+	case <-expired:
+		return 0, 0, false, nil
+	}
+}
+
+// close stops reading keys
+func (k *keyReader) close() {
+	if k.keys != nil {
+		keyboard.Close()
+		k.keys = nil
+	}
+}
+
 // KeyboardHandler implements the InputHandler interface for keyboard input
 type KeyboardHandler struct {
-	initialized bool
+	reader keyReader
 }
 
 // NewKeyboardHandler creates a new keyboard input handler
@@ -18,78 +72,78 @@ func NewKeyboardHandler() *KeyboardHandler {
 
 // Initialize sets up the keyboard handler
 func (h *KeyboardHandler) Initialize() error {
-	if h.initialized {
-		return nil
-	}
-
-	if err := keyboard.Open(); err != nil {
-		return err
-	}
-
-	h.initialized = true
-	return nil
+	return h.reader.open()
 }
 
 // GetNextAction waits for and returns the next player action
 func (h *KeyboardHandler) GetNextAction() (game.Action, error) {
-	if !h.initialized {
-		if err := h.Initialize(); err != nil {
-			return game.ActionNone, err
-		}
-	}
+	return h.WaitForAction(0)
+}
 
-	char, key, err := keyboard.GetKey()
-	if err != nil {
+// WaitForAction is like GetNextAction but returns ActionNone if no key is pressed within
+// the timeout. A timeout of 0 waits forever.
+func (h *KeyboardHandler) WaitForAction(timeout time.Duration) (game.Action, error) {
+	char, key, ok, err := h.reader.next(timeout)
+	if err != nil || !ok {
 		return game.ActionNone, err
 	}
+	return playAction(char, key), nil
+}
 
+// playAction maps a key press to a player action
+func playAction(char rune, key keyboard.Key) game.Action {
 	// Handle special keys first
 	switch key {
 	case keyboard.KeyArrowLeft:
-		return game.ActionMoveLeft, nil
+		return game.ActionMoveLeft
 	case keyboard.KeyArrowRight:
-		return game.ActionMoveRight, nil
+		return game.ActionMoveRight
 	case keyboard.KeyArrowUp:
-		return game.ActionMoveColumnUp, nil
+		return game.ActionMoveColumnUp
 	case keyboard.KeyArrowDown:
-		return game.ActionMoveColumnDown, nil
+		return game.ActionMoveColumnDown
 	case keyboard.KeyCtrlC:
-		return game.ActionQuit, nil
+		return game.ActionQuit
 	}
 
 	// Handle character input (including tmux-friendly alternatives)
 	switch char {
 	case 'q', 'Q':
-		return game.ActionQuit, nil
+		return game.ActionQuit
 	case 27: // ESC character
-		return game.ActionQuit, nil
+		return game.ActionQuit
 	// Alternative controls for tmux compatibility
 	case 'a', 'A': // Move left
-		return game.ActionMoveLeft, nil
+		return game.ActionMoveLeft
 	case 'd', 'D': // Move right
-		return game.ActionMoveRight, nil
+		return game.ActionMoveRight
 	case 'w', 'W': // Move column up
-		return game.ActionMoveColumnUp, nil
+		return game.ActionMoveColumnUp
 	case 's', 'S': // Move column down
-		return game.ActionMoveColumnDown, nil
+		return game.ActionMoveColumnDown
 	case 'h': // Vi-style left
-		return game.ActionMoveLeft, nil
+		return game.ActionMoveLeft
 	case 'l': // Vi-style right
-		return game.ActionMoveRight, nil
+		return game.ActionMoveRight
 	case 'k': // Vi-style up
-		return game.ActionMoveColumnUp, nil
+		return game.ActionMoveColumnUp
 	case 'j': // Vi-style down
-		return game.ActionMoveColumnDown, nil
+		return game.ActionMoveColumnDown
+	case 'u', 'U': // Take back the last move
+		return game.ActionUndo
+	case 'r', 'R': // Replay a move taken back
+		return game.ActionRedo
+	case '?': // Suggest a move
+		return game.ActionHint
+	case 'e', 'E': // Evaluate every move
+		return game.ActionToggleAnalysis
 	}
 
-	return game.ActionNone, nil
+	return game.ActionNone
 }
 
 // Close shuts down the keyboard handler
 func (h *KeyboardHandler) Close() error {
-	if h.initialized {
-		keyboard.Close()
-		h.initialized = false
-	}
+	h.reader.close()
 	return nil
 }
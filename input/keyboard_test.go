@@ -0,0 +1,35 @@
+package input
+
+import (
+	"testing"
+
+	"micemen/game"
+
+	"github.com/eiannone/keyboard"
+)
+
+func TestPlayAction(t *testing.T) {
+	tests := []struct {
+		char rune
+		key  keyboard.Key
+		want game.Action
+	}{
+		{0, keyboard.KeyArrowLeft, game.ActionMoveLeft},
+		{0, keyboard.KeyArrowUp, game.ActionMoveColumnUp},
+		{'s', 0, game.ActionMoveColumnDown},
+		{'l', 0, game.ActionMoveRight},
+		{'u', 0, game.ActionUndo},
+		{'R', 0, game.ActionRedo},
+		{'?', 0, game.ActionHint},
+		{'e', 0, game.ActionToggleAnalysis},
+		{'q', 0, game.ActionQuit},
+		{0, keyboard.KeyCtrlC, game.ActionQuit},
+		{'x', 0, game.ActionNone},
+	}
+	
+	for _, tt := range tests {
+		if got := playAction(tt.char, tt.key); got != tt.want {
+			t.Errorf("Key %q (%d) should map to action %d, got %d", tt.char, tt.key, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package input
+
+import (
+	"time"
+
+	"micemen/game"
+
+	"github.com/eiannone/keyboard"
+)
+
+// ReplayKeyboardHandler implements the InputHandler interface for the replay viewer, which
+// steps through a recorded game instead of playing one
+type ReplayKeyboardHandler struct {
+	reader keyReader
+}
+
+// NewReplayKeyboardHandler creates a new replay input handler
+func NewReplayKeyboardHandler() *ReplayKeyboardHandler {
+	return &ReplayKeyboardHandler{}
+}
+
+// Initialize sets up the keyboard
+func (h *ReplayKeyboardHandler) Initialize() error {
+	return h.reader.open()
+}
+
+// GetNextAction waits for and returns the next viewer action
+func (h *ReplayKeyboardHandler) GetNextAction() (game.Action, error) {
+	return h.WaitForAction(0)
+}
+
+// WaitForAction is like GetNextAction but returns ActionNone if no key is pressed within
+// the timeout, which lets the viewer auto-play between key presses
+func (h *ReplayKeyboardHandler) WaitForAction(timeout time.Duration) (game.Action, error) {
+	char, key, ok, err := h.reader.next(timeout)
+	if err != nil || !ok {
+		return game.ActionNone, err
+	}
+	return replayAction(char, key), nil
+}
+
+// replayAction maps a key press to a viewer action
+func replayAction(char rune, key keyboard.Key) game.Action {
+	switch key {
+	case keyboard.KeyArrowLeft:
+		return game.ActionStepBack
+	case keyboard.KeyArrowRight:
+		return game.ActionStepForward
+	case keyboard.KeyHome, keyboard.KeyArrowUp:
+		return game.ActionJumpToStart
+	case keyboard.KeyEnd, keyboard.KeyArrowDown:
+		return game.ActionJumpToEnd
+	case keyboard.KeySpace, keyboard.KeyEnter:
+		return game.ActionTogglePlay
+	case keyboard.KeyCtrlC, keyboard.KeyEsc:
+		return game.ActionQuit
+	}
+
+	switch char {
+	case 'q', 'Q':
+		return game.ActionQuit
+	case 'a', 'A', 'h': // Step back
+		return game.ActionStepBack
+	case 'd', 'D', 'l': // Step forward
+		return game.ActionStepForward
+	case 'g', '<': // Jump to the start
+		return game.ActionJumpToStart
+	case 'G', '>': // Jump to the end
+		return game.ActionJumpToEnd
+	case 'p', 'P': // Play or pause
+		return game.ActionTogglePlay
+	}
+
+	return game.ActionNone
+}
+
+// Close shuts down the keyboard
+func (h *ReplayKeyboardHandler) Close() error {
+	h.reader.close()
+	return nil
+}
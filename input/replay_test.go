@@ -0,0 +1,40 @@
+package input
+
+import (
+	"testing"
+
+	"micemen/game"
+
+	"github.com/eiannone/keyboard"
+)
+
+func TestReplayAction(t *testing.T) {
+	tests := []struct {
+		char rune
+		key  keyboard.Key
+		want game.Action
+	}{
+		{0, keyboard.KeyArrowLeft, game.ActionStepBack},
+		{0, keyboard.KeyArrowRight, game.ActionStepForward},
+		{0, keyboard.KeyHome, game.ActionJumpToStart},
+		{0, keyboard.KeyArrowUp, game.ActionJumpToStart},
+		{0, keyboard.KeyEnd, game.ActionJumpToEnd},
+		{0, keyboard.KeySpace, game.ActionTogglePlay},
+		{0, keyboard.KeyEnter, game.ActionTogglePlay},
+		{0, keyboard.KeyEsc, game.ActionQuit},
+		{'h', 0, game.ActionStepBack},
+		{'D', 0, game.ActionStepForward},
+		{'g', 0, game.ActionJumpToStart},
+		{'G', 0, game.ActionJumpToEnd},
+		{'>', 0, game.ActionJumpToEnd},
+		{'p', 0, game.ActionTogglePlay},
+		{'Q', 0, game.ActionQuit},
+		{'w', 0, game.ActionNone},
+	}
+	
+	for _, tt := range tests {
+		if got := replayAction(tt.char, tt.key); got != tt.want {
+			t.Errorf("Key %q (%d) should map to viewer action %d, got %d", tt.char, tt.key, tt.want, got)
+		}
+	}
+}
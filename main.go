@@ -1,12 +1,18 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"micemen/ai"
+	"micemen/book"
 	"micemen/game"
 	"micemen/input"
 	"micemen/render"
+	"micemen/tablebase"
 )
 
 // GameEngine coordinates the game components
@@ -14,18 +20,37 @@ type GameEngine struct {
 	game   game.Game
 	render game.Renderer
 	input  game.InputHandler
+
+	bots     [2]ai.Bot     // Bot playing each color, indexed by PlayerColor; nil for a human
+	botDelay time.Duration // Pause before each bot move so people can follow the game
+	botStats string        // Statistics of the last bot search, shown under the board
+
+	endgame *tablebase.Solver // Shows the moves to mate in solved endgames, or nil
+	hints   int               // Hints asked for this game
+
+	analysis *analyzer // Background move scoring for the analysis overlay, or nil when off
+
+	savePath string // Autosave file written after every move, or "" to not autosave
+	saved    bool   // Whether this run has written the autosave file
 }
 
 // NewGameEngine creates a new game engine with all components
-func NewGameEngine() *GameEngine {
-	gameInstance := game.NewGame()
+func NewGameEngine(gameInstance game.Game) *GameEngine {
 	return &GameEngine{
 		game:   gameInstance,
 		render: render.NewTerminalRenderer(gameInstance), // Pass game to renderer
 		input:  input.NewKeyboardHandler(),
+
+		botDelay: defaultBotDelay,
 	}
 }
 
+// defaultBotDelay is how long the engine pauses before each bot move
+const defaultBotDelay = 500 * time.Millisecond
+
+// hintThinkTime is how long the engine searches for a hint
+const hintThinkTime = time.Second
+
 // Run executes the main game loop
 func (e *GameEngine) Run() error {
 	// Initialize input handler
@@ -37,38 +62,344 @@ func (e *GameEngine) Run() error {
 	// Set up terminal
 	if termRender, ok := e.render.(*render.TerminalRenderer); ok {
 		termRender.HideCursor()
-		defer func() {
-			termRender.ShowCursor()
-			termRender.Clear()
-		}()
+		defer termRender.ShowCursor()
 	}
 
 	// Initial render
-	e.render.Render(e.game.GetState())
+	e.renderState()
 
 	// Main game loop
 	for !e.game.IsGameOver() {
-		action, err := e.input.GetNextAction()
+		var err error
+		if bot := e.bots[e.game.GetState().CurrentPlayer]; bot != nil {
+			err = e.playBotTurn(bot)
+		} else {
+			err = e.playHumanTurn()
+		}
 		if err != nil {
-			return fmt.Errorf("input error: %w", err)
+			return err
+		}
+	}
+
+	e.showResult(e.game.GetState())
+	return nil
+}
+
+// renderState shows the current position, with the last bot search under it
+func (e *GameEngine) renderState() {
+	state := e.game.GetState()
+	if e.analysis != nil {
+		e.analysis.start(state)
+	}
+	e.render.Render(state)
+	if e.endgame != nil {
+		if result, ok := e.endgame.ProbeInBackground(state); ok {
+			e.render.ShowMessage("\n🏁 " + result.Describe(state.CurrentPlayer))
 		}
+	}
+	if e.botStats != "" {
+		e.render.ShowMessage("\n" + e.botStats)
+	}
+}
 
-		if action != game.ActionNone {
-			e.game.ProcessAction(action)
-			if !e.game.IsGameOver() {
-				e.render.Render(e.game.GetState())
+// watchBotSearches collects the search statistics of bots that report them, for display
+// under the board
+func (e *GameEngine) watchBotSearches() {
+	for _, bot := range e.bots {
+		if mcts, ok := searchingBot(bot).(*ai.MCTSBot); ok {
+			mcts.OnSearch = func(stats ai.SearchStats) {
+				e.botStats = stats.String()
 			}
 		}
 	}
+}
 
-	e.render.ShowMessage("Thanks for playing Micemen!")
+// searchingBot returns the bot under any opening book or tablebase wrappers
+func searchingBot(bot ai.Bot) ai.Bot {
+	for {
+		switch b := bot.(type) {
+		case *book.Bot:
+			bot = b.Fallback
+		case *tablebase.Bot:
+			bot = b.Fallback
+		default:
+			return bot
+		}
+	}
+}
+
+// playHumanTurn handles one key press from the player to move. While the analysis
+// overlay or the endgame solver is working, it stops waiting now and then to show their
+// results once they're in.
+func (e *GameEngine) playHumanTurn() error {
+	if e.backgroundPending() {
+		action, err := e.input.WaitForAction(analysisPoll)
+		if err != nil {
+			return fmt.Errorf("input error: %w", err)
+		}
+		if action == game.ActionNone {
+			if e.collectBackground() {
+				e.renderState()
+			}
+			return nil
+		}
+		e.handleAction(action)
+		return nil
+	}
+
+	action, err := e.input.GetNextAction()
+	if err != nil {
+		return fmt.Errorf("input error: %w", err)
+	}
+
+	e.handleAction(action)
 	return nil
 }
 
+// backgroundPending reports whether the analysis overlay or the endgame solver is still
+// working in the background
+func (e *GameEngine) backgroundPending() bool {
+	return e.analysis != nil && e.analysis.pending() || e.endgame != nil && e.endgame.Solving()
+}
+
+// collectBackground picks up the background work that's finished and reports whether the
+// screen needs redrawing to show it
+func (e *GameEngine) collectBackground() bool {
+	changed := e.analysis != nil && e.analysis.collect(e.game.GetState())
+	if e.endgame != nil && e.endgame.Collect() {
+		changed = true
+	}
+	return changed
+}
+
+// playBotTurn lets a bot move. Keys pressed while it waits to move are handled first, so
+// people can still quit or take moves back.
+func (e *GameEngine) playBotTurn(bot ai.Bot) error {
+	action, err := e.input.WaitForAction(e.botDelay)
+	if err != nil {
+		return fmt.Errorf("input error: %w", err)
+	}
+	if action != game.ActionNone {
+		e.handleAction(action)
+		return nil
+	}
+	if e.collectBackground() {
+		e.renderState()
+	}
+
+	move, err := bot.ChooseMove(e.game)
+	if err != nil {
+		return fmt.Errorf("%s bot: %w", bot.Name(), err)
+	}
+	if err := e.game.ApplyMove(move); err != nil {
+		return fmt.Errorf("%s bot played %s: %w", bot.Name(), move, err)
+	}
+
+	if !e.game.IsGameOver() {
+		e.renderState()
+		e.autosave()
+	}
+	return nil
+}
+
+// handleAction applies a player action and shows the result
+func (e *GameEngine) handleAction(action game.Action) {
+	switch action {
+	case game.ActionNone:
+		return
+	case game.ActionHint:
+		e.showHint()
+		return
+	case game.ActionToggleAnalysis:
+		e.toggleAnalysis()
+		return
+	}
+
+	e.game.ProcessAction(action)
+	switch action {
+	case game.ActionUndo:
+		e.skipBotTurns(e.game.Undo)
+	case game.ActionRedo:
+		e.skipBotTurns(e.game.Redo)
+	}
+
+	if e.game.IsGameOver() {
+		return
+	}
+	e.renderState()
+
+	switch action {
+	case game.ActionMoveColumnUp, game.ActionMoveColumnDown, game.ActionUndo, game.ActionRedo:
+		e.autosave()
+	}
+}
+
+// showHint searches for a good move for the person to move and highlights it on the
+// board, leaving it to them to play
+func (e *GameEngine) showHint() {
+	state := e.game.GetState()
+	termRender, ok := e.render.(*render.TerminalRenderer)
+	if !ok || e.bots[state.CurrentPlayer] != nil {
+		return
+	}
+
+	e.render.ShowMessage("💡 Looking for a good move...")
+	var bot ai.Bot = ai.NewMCTSBot(hintThinkTime)
+	if e.endgame != nil {
+		bot = tablebase.NewBot(e.endgame, bot)
+	}
+	move, err := bot.ChooseMove(e.game)
+	if err != nil {
+		e.render.ShowMessage(fmt.Sprintf("⚠️  Couldn't find a hint: %v", err))
+		return
+	}
+
+	e.hints++
+	termRender.SetHint(state, move)
+	e.renderState()
+}
+
+// skipBotTurns keeps undoing or redoing until it's a person's turn again, so taking back
+// a move against a bot also takes back the bot's reply. Games between two bots are
+// stepped one move at a time.
+func (e *GameEngine) skipBotTurns(step func() bool) {
+	for {
+		player := e.game.GetState().CurrentPlayer
+		if e.bots[player] == nil || e.bots[player.Opponent()] != nil || !step() {
+			return
+		}
+	}
+}
+
+// autosave saves the game so it can be resumed
+func (e *GameEngine) autosave() {
+	if e.savePath == "" {
+		return
+	}
+
+	if err := saveGame(e.savePath, e.game); err != nil {
+		e.render.ShowMessage(fmt.Sprintf("⚠️  Couldn't autosave the game: %v", err))
+		return
+	}
+	e.saved = true
+}
+
+// showResult displays how the game ended
+func (e *GameEngine) showResult(state game.GameState) {
+	if state.Result != game.ResultAllMiceHome {
+		e.render.Clear()
+		e.render.ShowMessage("Thanks for playing Micemen!")
+		if e.saved {
+			e.render.ShowMessage("Your game is saved. Run micemen --resume to pick it up again.")
+		}
+		e.showHintCount()
+		return
+	}
+
+	// A finished game has nothing left to resume
+	if e.savePath != "" {
+		os.Remove(e.savePath)
+	}
+
+	// Leave the final board on screen under the result
+	e.render.Render(state)
+	loser := state.Winner.Opponent()
+	e.render.ShowMessage(fmt.Sprintf("\n🏆 %s wins! All %d mice made it home (%s got %d home).",
+		state.Winner, state.Escaped[state.Winner], loser, state.Escaped[loser]))
+	e.showHintCount()
+}
+
+// showHintCount tells how many hints were used, if any
+func (e *GameEngine) showHintCount() {
+	switch e.hints {
+	case 0:
+	case 1:
+		e.render.ShowMessage("💡 1 hint used")
+	default:
+		e.render.ShowMessage(fmt.Sprintf("💡 %d hints used", e.hints))
+	}
+}
+
+// errUsage reports a command line that doesn't match a command's usage
+var errUsage = errors.New("invalid usage")
+
 func main() {
-	engine := NewGameEngine()
-	if err := engine.Run(); err != nil {
+	var err error
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "record":
+			err = runRecord(os.Args[2:])
+		case "replay":
+			err = runReplay(os.Args[2:])
+		case "book":
+			err = runBook(os.Args[2:])
+		case "tablebase":
+			err = runTablebase(os.Args[2:])
+		case "tournament":
+			err = runTournament(os.Args[2:])
+		default:
+			err = runPlay(os.Args[1:])
+		}
+	} else {
+		err = runPlay(nil)
+	}
+
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runPlay starts an interactive game
+func runPlay(args []string) error {
+	fs := flag.NewFlagSet("micemen", flag.ExitOnError)
+	opts := addGameFlags(fs)
+	players := addPlayerFlags(fs)
+	resume := fs.Bool("resume", false, "continue the last unfinished game")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: micemen [flags]")
+		fmt.Fprintln(fs.Output(), "       micemen record save [flags] <file>")
+		fmt.Fprintln(fs.Output(), "       micemen replay [flags] <file>")
+		fmt.Fprintln(fs.Output(), "       micemen book build|inspect|merge ...")
+		fmt.Fprintln(fs.Output(), "       micemen tablebase solve|probe [flags] <file>")
+		fmt.Fprintln(fs.Output(), "       micemen tournament [flags] <bot> <bot>...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *resume && opts.boardGiven() {
+		fmt.Fprintln(fs.Output(), "-resume continues the saved board, so it can't be combined with board flags")
+		return errUsage
+	}
+
+	bots, err := players.bots()
+	if err != nil {
+		return err
+	}
+
+	savePath, err := autosavePath()
+	if err != nil {
+		return fmt.Errorf("finding the autosave file: %w", err)
+	}
+
+	// Board flags ask for a new game, so only offer the saved one when there are none
+	var g *game.MicemenGame
+	if *resume || !opts.boardGiven() && askResume(savePath) {
+		g, err = loadSavedGame(savePath)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("there is no saved game to resume")
+		}
+	} else {
+		g, err = opts.newGame()
+	}
+	if err != nil {
+		return err
+	}
+
+	engine := NewGameEngine(g)
+	players.configure(engine, bots)
+	engine.savePath = savePath
+	return engine.Run()
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"micemen/ai"
+	"micemen/book"
+	"micemen/game"
+	"micemen/tablebase"
+)
+
+// gameOptions holds the command-line flags that set up a board
+type gameOptions struct {
+	flags     *flag.FlagSet
+	seed      *int64
+	width     *int
+	height    *int
+	mice      *int
+	fair      *bool
+	generator *string
+	mapPath   *string
+}
+
+// addGameFlags registers the board setup flags on a flag set
+func addGameFlags(fs *flag.FlagSet) *gameOptions {
+	return &gameOptions{
+		flags:     fs,
+		seed:      fs.Int64("seed", 0, "seed for board generation, to replay a board (default random)"),
+		width:     fs.Int("width", game.DefaultGridWidth, "board width in columns"),
+		height:    fs.Int("height", game.DefaultGridHeight, "board height in rows"),
+		mice:      fs.Int("mice", 0, "mice per player (default scales with the board)"),
+		fair:      fs.Bool("fair", false, "mirror the board so both sides face the same terrain (with -map, require a mirrored map)"),
+		generator: fs.String("generator", "uniform", "board layout: "+generatorNames()),
+		mapPath:   fs.String("map", "", "play on a hand-made board from a map file instead"),
+	}
+}
+
+// newGame sets up a game from the parsed flags
+func (o *gameOptions) newGame() (*game.MicemenGame, error) {
+	cfg, gen, err := o.board()
+	if err != nil {
+		return nil, err
+	}
+	g := game.NewGameWithSeed(cfg, gen, o.boardSeed())
+
+	// A generator that ignores the mirroring would hand one side the better terrain
+	if *o.fair {
+		if err := game.CheckFairness(g.GetState()); err != nil {
+			return nil, fmt.Errorf("board isn't fair: %w", err)
+		}
+	}
+	return g, nil
+}
+
+// board returns the board size and layout chosen by the flags
+func (o *gameOptions) board() (game.Config, game.BoardGenerator, error) {
+	if *o.mapPath != "" {
+		// The map decides the board size and layout
+		m, err := game.LoadMap(*o.mapPath)
+		if err != nil {
+			return game.Config{}, nil, fmt.Errorf("invalid map: %w", err)
+		}
+		if *o.fair {
+			if err := game.CheckFairness(m.State); err != nil {
+				return game.Config{}, nil, fmt.Errorf("map isn't fair: %w", err)
+			}
+		}
+		return m.State.Config, game.MapGenerator{Map: m}, nil
+	}
+
+	gen, err := game.GeneratorByName(*o.generator)
+	if err != nil {
+		return game.Config{}, nil, err
+	}
+
+	cfg := game.NewConfig(*o.width, *o.height, *o.mice)
+	cfg.Symmetric = *o.fair
+	if err := cfg.Validate(); err != nil {
+		return game.Config{}, nil, fmt.Errorf("invalid board: %w", err)
+	}
+	return cfg, gen, nil
+}
+
+// seedGiven reports whether the seed flag was given, so 0 is still a usable seed
+func (o *gameOptions) seedGiven() bool {
+	return o.given("seed")
+}
+
+// boardGiven reports whether any flag choosing the board was given, which asks for a new
+// game rather than the saved one
+func (o *gameOptions) boardGiven() bool {
+	return o.given("seed", "width", "height", "mice", "fair", "generator", "map")
+}
+
+// given reports whether any of the named flags was set on the command line
+func (o *gameOptions) given(names ...string) bool {
+	given := false
+	o.flags.Visit(func(f *flag.Flag) {
+		if slices.Contains(names, f.Name) {
+			given = true
+		}
+	})
+	return given
+}
+
+// boardSeed returns the seed flag if it was given, or a random seed
+func (o *gameOptions) boardSeed() int64 {
+	if o.seedGiven() {
+		return *o.seed
+	}
+	return time.Now().UnixNano()
+}
+
+// playerOptions holds the command-line flags that choose who plays each side
+type playerOptions struct {
+	red       *string
+	blue      *string
+	botDelay  *time.Duration
+	debug     *bool
+	bookPath  *string
+	endgame   *bool
+	tablePath *string
+
+	solver *tablebase.Solver // Endgame solver shared by the bots and the engine, set by bots
+}
+
+// addPlayerFlags registers the player flags on a flag set
+func addPlayerFlags(fs *flag.FlagSet) *playerOptions {
+	players := ai.Human + " or a bot: " + strings.Join(ai.BotNames(), ", ")
+	return &playerOptions{
+		red:       fs.String("red", ai.Human, "who plays Red: "+players),
+		blue:      fs.String("blue", ai.Human, "who plays Blue: "+players),
+		botDelay:  fs.Duration("bot-delay", defaultBotDelay, "pause before each bot move"),
+		debug:     fs.Bool("debug", false, "show the search statistics of bots that report them"),
+		bookPath:  fs.String("book", "", "opening book for the bots to play their first moves from"),
+		endgame:   fs.Bool("endgame", false, "solve endgames of up to 2 mice a side: bots play them perfectly and the moves to mate are shown"),
+		tablePath: fs.String("tablebase", "", "solved endgames to start from, from micemen tablebase solve (implies -endgame)"),
+	}
+}
+
+// bots creates the bot for each color, indexed by PlayerColor, leaving humans nil
+func (o *playerOptions) bots() ([2]ai.Bot, error) {
+	var bots [2]ai.Bot
+	var openings *book.Book
+	if *o.bookPath != "" {
+		var err error
+		if openings, err = book.Load(*o.bookPath); err != nil {
+			return bots, fmt.Errorf("invalid opening book: %w", err)
+		}
+	}
+	if *o.tablePath != "" {
+		table, err := tablebase.Load(*o.tablePath)
+		if err != nil {
+			return bots, fmt.Errorf("invalid tablebase: %w", err)
+		}
+		o.solver = tablebase.NewSolver(table)
+	} else if *o.endgame {
+		o.solver = tablebase.NewSolver(tablebase.NewTable())
+	}
+
+	for _, player := range []game.PlayerColor{game.Red, game.Blue} {
+		spec := *o.red
+		if player == game.Blue {
+			spec = *o.blue
+		}
+		if spec == ai.Human {
+			continue
+		}
+
+		bot, err := ai.New(spec)
+		if err != nil {
+			return bots, fmt.Errorf("invalid %s player: %w", player, err)
+		}
+		if o.solver != nil {
+			bot = tablebase.NewBot(o.solver, bot)
+		}
+		if openings != nil {
+			bot = book.NewBot(openings, bot)
+		}
+		bots[player] = bot
+	}
+	return bots, nil
+}
+
+// configure hands the bots and bot settings to the engine
+func (o *playerOptions) configure(e *GameEngine, bots [2]ai.Bot) {
+	e.bots = bots
+	e.botDelay = *o.botDelay
+	e.endgame = o.solver
+	if *o.debug {
+		e.watchBotSearches()
+	}
+}
+
+// generatorNames lists the built-in board generators for the usage message
+func generatorNames() string {
+	var names []string
+	for _, gen := range game.Generators() {
+		names = append(names, gen.Name())
+	}
+	return strings.Join(names, ", ")
+}
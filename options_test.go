@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBoardGiven(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"-red", "alphabeta", "-resume"}, false},
+		{[]string{"-seed", "0"}, true},
+		{[]string{"-map", "maps/duel.map"}, true},
+		{[]string{"-fair"}, true},
+	}
+	
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("micemen", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		opts := addGameFlags(fs)
+		addPlayerFlags(fs)
+		fs.Bool("resume", false, "")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("Parsing %v failed: %v", tt.args, err)
+		}
+		if got := opts.boardGiven(); got != tt.want {
+			t.Errorf("Board flags given in %v should be %v, got %v", tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestFairChecksTheBoard(t *testing.T) {
+	lopsided := filepath.Join(t.TempDir(), "lopsided.map")
+	if err := os.WriteFile(lopsided, []byte("micemen-map 1\nmice: 1\n\n.....\nR...B\n#.###\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	
+	tests := []struct {
+		args []string
+		fair bool
+	}{
+		{[]string{"-fair", "-seed", "3"}, true},
+		{[]string{"-fair", "-map", "maps/duel.map"}, true},
+		{[]string{"-map", lopsided}, true},
+		{[]string{"-fair", "-map", lopsided}, false},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("micemen", flag.ContinueOnError)
+		opts := addGameFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("Parsing %v failed: %v", tt.args, err)
+		}
+		if _, err := opts.newGame(); (err == nil) != tt.fair {
+			t.Errorf("Setting up a game with %v should succeed only if it's allowed (%v), got %v", tt.args, tt.fair, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"micemen/record"
+)
+
+// runRecord handles "micemen record save [flags] <file>": play a game and save its record
+func runRecord(args []string) error {
+	if len(args) == 0 || args[0] != "save" {
+		fmt.Fprintln(os.Stderr, "Usage: micemen record save [flags] <file>")
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet("record save", flag.ExitOnError)
+	opts := addGameFlags(fs)
+	players := addPlayerFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: micemen record save [flags] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args[1:])
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	path := fs.Arg(0)
+
+	bots, err := players.bots()
+	if err != nil {
+		return err
+	}
+	g, err := opts.newGame()
+	if err != nil {
+		return err
+	}
+
+	engine := NewGameEngine(g)
+	players.configure(engine, bots)
+	if err := engine.Run(); err != nil {
+		return err
+	}
+
+	if err := record.Save(path, record.FromGame(g, *players.red, *players.blue)); err != nil {
+		return fmt.Errorf("saving record: %w", err)
+	}
+	fmt.Printf("Game record saved to %s\n", path)
+	return nil
+}
@@ -0,0 +1,339 @@
+package record
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"micemen/game"
+)
+
+// A game record is a PGN-like text file: "[Key "value"]" header lines, a blank line and
+// the moves in notation, numbered in pairs:
+//
+//	[Event "Casual game"]
+//	[Date "2026.10.16"]
+//	[Red "human"]
+//	[Blue "random"]
+//	[Result "Red"]
+//	[Board "19x13"]
+//	[Mice "12"]
+//	[Walls "5-8"]
+//	[Columns "9-9"]
+//	[Generator "uniform"]
+//	[Seed "42"]
+//
+//	1. 7U 12D 2. 5D 13U
+//
+// Boards that weren't generated from a seed, such as map boards, are stored in a Position
+// header instead of Generator and Seed, with the rows of the map joined by '/', plus Turn
+// and Home headers for the side to move and the mice already home.
+
+// ResultUnfinished is the Result of a game that nobody has won
+const ResultUnfinished = "*"
+
+// Record is an archived game: how the board was set up, who played and every move
+type Record struct {
+	Event  string
+	Date   string // YYYY.MM.DD
+	Red    string
+	Blue   string
+	Result string // Winning color, or ResultUnfinished
+
+	// The starting board is either generated from a seed...
+	Config    game.Config
+	Generator string
+	Seed      int64
+	// ...or given as a fixed position when Position is set
+	Position *game.Map
+
+	Moves []game.Move
+}
+
+// FromGame builds a record of the game played so far
+func FromGame(g game.Game, red, blue string) *Record {
+	initial := g.InitialState()
+	state := g.GetState()
+
+	rec := &Record{
+		Event:  "Casual game",
+		Date:   time.Now().Format("2006.01.02"),
+		Red:    red,
+		Blue:   blue,
+		Result: ResultUnfinished,
+		Config: initial.Config,
+		Moves:  g.Moves(),
+	}
+	if state.Result == game.ResultAllMiceHome {
+		rec.Result = state.Winner.String()
+	}
+
+	if initial.Generator == (game.MapGenerator{}).Name() {
+		rec.Position = &game.Map{State: initial}
+	} else {
+		rec.Generator = initial.Generator
+		rec.Seed = initial.Seed
+	}
+
+	return rec
+}
+
+// NewGame sets up the record's starting board
+func (r *Record) NewGame() (*game.MicemenGame, error) {
+	if r.Position != nil {
+		return game.NewGameWithSeed(r.Position.State.Config, game.MapGenerator{Map: r.Position}, 0), nil
+	}
+
+	gen, err := game.GeneratorByName(r.Generator)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid board: %w", err)
+	}
+	return game.NewGameWithSeed(r.Config, gen, r.Seed), nil
+}
+
+// Replay rebuilds the game by playing every move through the game engine
+func (r *Record) Replay() (*game.MicemenGame, error) {
+	g, err := r.NewGame()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, move := range r.Moves {
+		if err := g.ApplyMove(move); err != nil {
+			return nil, fmt.Errorf("move %d (%s): %w", i+1, move, err)
+		}
+	}
+	return g, nil
+}
+
+// Load reads a game record from disk
+func Load(path string) (*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rec, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rec, nil
+}
+
+// Save writes a game record to disk
+func Save(path string, rec *Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := Write(f, rec); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes a game record
+func Write(w io.Writer, rec *Record) error {
+	bw := bufio.NewWriter(w)
+
+	header := func(key, value string) {
+		fmt.Fprintf(bw, "[%s %q]\n", key, value)
+	}
+	header("Event", rec.Event)
+	header("Date", rec.Date)
+	header("Red", rec.Red)
+	header("Blue", rec.Blue)
+	header("Result", rec.Result)
+
+	if rec.Position != nil {
+		rows, err := positionRows(rec.Position)
+		if err != nil {
+			return err
+		}
+		state := rec.Position.State
+		header("Mice", strconv.Itoa(state.Config.MicePerPlayer))
+		header("Turn", state.CurrentPlayer.String())
+		header("Home", fmt.Sprintf("%d %d", state.Escaped[game.Red], state.Escaped[game.Blue]))
+		header("Position", strings.Join(rows, "/"))
+	} else {
+		cfg := rec.Config
+		header("Board", fmt.Sprintf("%dx%d", cfg.Width, cfg.Height))
+		header("Mice", strconv.Itoa(cfg.MicePerPlayer))
+		header("Walls", fmt.Sprintf("%d-%d", cfg.MinWalls, cfg.MaxWalls))
+		header("Columns", fmt.Sprintf("%d-%d", cfg.Player1Columns, cfg.Player2Columns))
+		if cfg.Symmetric {
+			header("Symmetric", "yes")
+		}
+		header("Generator", rec.Generator)
+		header("Seed", strconv.FormatInt(rec.Seed, 10))
+	}
+	fmt.Fprintln(bw)
+
+	// Moves numbered in pairs, wrapped to keep lines short
+	line := ""
+	for i, move := range rec.Moves {
+		token := move.String()
+		if i%2 == 0 {
+			token = fmt.Sprintf("%d. %s", i/2+1, token)
+		}
+		if line != "" && len(line)+1+len(token) > 78 {
+			fmt.Fprintln(bw, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += token
+	}
+	if line != "" {
+		fmt.Fprintln(bw, line)
+	}
+
+	return bw.Flush()
+}
+
+// positionRows returns the board rows of a map as written in a map file
+func positionRows(m *game.Map) ([]string, error) {
+	var buf bytes.Buffer
+	if err := game.WriteMap(&buf, &game.Map{State: m.State}); err != nil {
+		return nil, err
+	}
+
+	// The rows follow the map's header and the blank line after it
+	_, board, _ := strings.Cut(buf.String(), "\n\n")
+	return strings.Fields(board), nil
+}
+
+// Read parses a game record
+func Read(r io.Reader) (*Record, error) {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	headers := make(map[string]string)
+	headerLines := make(map[string]int)
+
+	rec := &Record{Result: ResultUnfinished}
+
+	// Header lines, then the move text
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			key, value, err := parseHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			headers[key] = value
+			headerLines[key] = lineNo
+			continue
+		}
+
+		for _, token := range strings.Fields(line) {
+			// Skip move numbers such as "12."
+			if strings.HasSuffix(token, ".") {
+				if _, err := strconv.Atoi(strings.TrimSuffix(token, ".")); err == nil {
+					continue
+				}
+			}
+			move, err := game.ParseMove(token)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			rec.Moves = append(rec.Moves, move)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	rec.Event = headers["Event"]
+	rec.Date = headers["Date"]
+	rec.Red = headers["Red"]
+	rec.Blue = headers["Blue"]
+	if result, ok := headers["Result"]; ok {
+		rec.Result = result
+	}
+
+	// headerError reports a bad header value at the line it came from
+	headerError := func(key string, err error) error {
+		if _, ok := headers[key]; !ok {
+			return fmt.Errorf("missing %s header", key)
+		}
+		return fmt.Errorf("line %d: invalid %s header: %w", headerLines[key], key, err)
+	}
+
+	if rows, ok := headers["Position"]; ok {
+		// Rebuild the map file the position came from
+		var text strings.Builder
+		text.WriteString("micemen-map 1\n")
+		for _, key := range []string{"Turn", "Mice", "Home"} {
+			if value, ok := headers[key]; ok {
+				fmt.Fprintf(&text, "%s: %s\n", strings.ToLower(key), value)
+			}
+		}
+		fmt.Fprintf(&text, "\n%s\n", strings.ReplaceAll(rows, "/", "\n"))
+
+		m, err := game.ReadMap(strings.NewReader(text.String()))
+		if err != nil {
+			return nil, headerError("Position", err)
+		}
+		rec.Position = m
+		return rec, nil
+	}
+
+	cfg := game.Config{}
+	if _, err := fmt.Sscanf(headers["Board"], "%dx%d", &cfg.Width, &cfg.Height); err != nil {
+		return nil, headerError("Board", err)
+	}
+	if _, err := fmt.Sscanf(headers["Mice"], "%d", &cfg.MicePerPlayer); err != nil {
+		return nil, headerError("Mice", err)
+	}
+	if _, err := fmt.Sscanf(headers["Walls"], "%d-%d", &cfg.MinWalls, &cfg.MaxWalls); err != nil {
+		return nil, headerError("Walls", err)
+	}
+	if _, err := fmt.Sscanf(headers["Columns"], "%d-%d", &cfg.Player1Columns, &cfg.Player2Columns); err != nil {
+		return nil, headerError("Columns", err)
+	}
+	cfg.Symmetric = headers["Symmetric"] == "yes"
+	rec.Config = cfg
+
+	rec.Generator = headers["Generator"]
+	seed, err := strconv.ParseInt(headers["Seed"], 10, 64)
+	if err != nil {
+		return nil, headerError("Seed", err)
+	}
+	rec.Seed = seed
+
+	return rec, nil
+}
+
+// parseHeader splits a [Key "value"] header line
+func parseHeader(line string) (string, string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("header %q is missing its closing ]", line)
+	}
+
+	key, quoted, ok := strings.Cut(line[1:len(line)-1], " ")
+	if !ok {
+		return "", "", fmt.Errorf("header %q has no value", line)
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return "", "", fmt.Errorf("header %q value must be quoted", line)
+	}
+	return key, value, nil
+}
@@ -0,0 +1,137 @@
+package record
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"micemen/game"
+)
+
+// playGame plays the first valid column of each player, alternating directions
+func playGame(g *game.MicemenGame, moves int) {
+	for i := 0; i < moves && !g.IsGameOver(); i++ {
+		state := g.GetState()
+		dir := game.DirectionUp
+		if i%2 == 1 {
+			dir = game.DirectionDown
+		}
+		g.ApplyMove(game.Move{Column: g.GetValidColumnsForPlayer(state.CurrentPlayer)[0], Direction: dir})
+	}
+}
+
+// sameBoard checks if two states have the same walls, mice and turn
+func sameBoard(a, b game.GameState) bool {
+	if len(a.Mice) != len(b.Mice) || a.CurrentPlayer != b.CurrentPlayer || a.Escaped != b.Escaped {
+		return false
+	}
+	for i := range a.Mice {
+		if a.Mice[i] != b.Mice[i] {
+			return false
+		}
+	}
+	for row := range a.Grid {
+		for col := range a.Grid[row] {
+			if a.Grid[row][col] != b.Grid[row][col] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	cfg := game.NewConfig(11, 9, 0)
+	cfg.Symmetric = true
+	original := game.NewGameWithSeed(cfg, game.CavesGenerator{}, 77)
+	playGame(original, 15)
+	
+	var buf bytes.Buffer
+	if err := Write(&buf, FromGame(original, "human", "random")); err != nil {
+		t.Fatalf("Writing record failed: %v", err)
+	}
+	rec, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Written record should load: %v", err)
+	}
+	
+	if rec.Red != "human" || rec.Blue != "random" || rec.Generator != "caves" || rec.Seed != 77 {
+		t.Errorf("Headers should survive the round trip, got %+v", rec)
+	}
+	if rec.Config != cfg {
+		t.Errorf("Config should be %+v, got %+v", cfg, rec.Config)
+	}
+	
+	replayed, err := rec.Replay()
+	if err != nil {
+		t.Fatalf("Replaying record failed: %v", err)
+	}
+	if !sameBoard(replayed.GetState(), original.GetState()) {
+		t.Error("Replaying the record should rebuild the exact game state")
+	}
+}
+
+func TestRecordFromMapPosition(t *testing.T) {
+	m, err := game.LoadMap("../maps/duel.map")
+	if err != nil {
+		t.Fatalf("Example map should load: %v", err)
+	}
+	original := game.NewGameWithSeed(m.State.Config, game.MapGenerator{Map: m}, 0)
+	playGame(original, 6)
+	
+	var buf bytes.Buffer
+	if err := Write(&buf, FromGame(original, "human", "human")); err != nil {
+		t.Fatalf("Writing record failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "[Position ") {
+		t.Fatalf("Map games should be recorded with their position:\n%s", buf.String())
+	}
+	
+	rec, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Written record should load: %v", err)
+	}
+	replayed, err := rec.Replay()
+	if err != nil {
+		t.Fatalf("Replaying record failed: %v", err)
+	}
+	if !sameBoard(replayed.GetState(), original.GetState()) {
+		t.Error("Replaying the record should rebuild the exact game state")
+	}
+}
+
+func TestReplayRejectsIllegalMove(t *testing.T) {
+	text := `[Event "Broken"]
+[Board "19x13"]
+[Mice "12"]
+[Walls "5-8"]
+[Columns "9-9"]
+[Generator "uniform"]
+[Seed "5"]
+
+1. 10U
+`
+	rec, err := Read(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Record should parse: %v", err)
+	}
+	
+	// Column 10 is the neutral middle column, which never starts with mice
+	_, err = rec.Replay()
+	if !errors.Is(err, game.ErrIllegalMove) || !strings.Contains(err.Error(), "move 1 (10U)") {
+		t.Errorf("Replay should fail on the illegal first move, got %v", err)
+	}
+}
+
+func TestReadRecordErrors(t *testing.T) {
+	for _, text := range []string{
+		"[Event \"No board\"]\n\n1. 3U\n",
+		"[Event unquoted]\n",
+		"[Board \"19x13\"]\n\n1. 3X\n",
+	} {
+		if _, err := Read(strings.NewReader(text)); err == nil {
+			t.Errorf("Record should be rejected:\n%s", text)
+		}
+	}
+}
@@ -2,12 +2,39 @@ package render
 
 import (
 	"fmt"
+	"math"
+
+	"micemen/ai"
 	"micemen/game"
 )
 
 // TerminalRenderer implements the Renderer interface for terminal output
 type TerminalRenderer struct {
 	game game.Game // Reference to game for querying state
+
+	hint     *game.Move // Suggested move, shown while the position it's for is on screen
+	hintHash uint64     // Hash of the position the hint is for
+
+	analysis *Analysis // Move scores for the analysis overlay, or nil when it's off
+}
+
+// Analysis is what the analysis overlay shows: the score of every legal move, for the
+// player to move
+type Analysis struct {
+	Hash   uint64         // Position the scores are for
+	Depth  int            // Moves searched ahead
+	Scores []ai.MoveScore // Nil while the scores are being worked out
+}
+
+// best returns the highest scoring move
+func (a *Analysis) best() ai.MoveScore {
+	best := a.Scores[0]
+	for _, score := range a.Scores[1:] {
+		if score.Score > best.Score {
+			best = score
+		}
+	}
+	return best
 }
 
 // NewTerminalRenderer creates a new terminal renderer
@@ -29,19 +56,93 @@ func (r *TerminalRenderer) Render(state game.GameState) {
 	if state.CurrentPlayer == game.Blue {
 		playerIcon = "🔹"
 	}
-	fmt.Printf("%s %s Player's Turn %s\n", playerIcon, state.CurrentPlayer.String(), playerIcon)
+	board := fmt.Sprintf("%s board, seed %d", state.Generator, state.Seed)
+	if state.Generator == (game.MapGenerator{}).Name() {
+		board = "map board"
+	}
+	fmt.Printf("%s %s Player's Turn %s   (%s)\n", playerIcon, state.CurrentPlayer.String(), playerIcon, board)
+
+	r.renderBoard(state)
+	r.showPlayerStats(state)
+	r.showHistory()
+	r.showTurnInfo(state)
+	r.showControls()
+}
+
+// SetHint highlights a suggested move whenever the given position is rendered
+func (r *TerminalRenderer) SetHint(state game.GameState, move game.Move) {
+	r.hint = &move
+	r.hintHash = state.Hash
+}
+
+// hintFor returns the suggested move for a position, or nil if there is none
+func (r *TerminalRenderer) hintFor(state game.GameState) *game.Move {
+	if r.hint == nil || r.hintHash != state.Hash {
+		return nil
+	}
+	return r.hint
+}
+
+// SetAnalysis shows the analysis overlay with the given scores, or hides it when nil
+func (r *TerminalRenderer) SetAnalysis(analysis *Analysis) {
+	r.analysis = analysis
+}
+
+// RenderReplay displays a position from a recorded game, headed by where it is in the
+// game and the move that led to it
+func (r *TerminalRenderer) RenderReplay(state game.GameState, info ReplayInfo) {
+	r.Clear()
+
+	status := "⏸️  Paused"
+	if info.Playing {
+		status = "▶️  Playing"
+	}
+	fmt.Printf("📼 Replay: move %d of %d   %s\n", info.Move, info.Total, status)
+	if info.Move > 0 {
+		fmt.Printf("   Last move: %s by %s\n", info.LastMove, info.LastMover)
+	} else {
+		fmt.Println("   Starting position")
+	}
+
+	r.renderBoard(state)
+	r.showPlayerStats(state)
+	if state.Result == game.ResultAllMiceHome {
+		fmt.Printf("\n🏆 %s wins!\n", state.Winner)
+	}
+	r.showReplayControls()
+}
 
+// ReplayInfo describes where the replay viewer is in a recorded game
+type ReplayInfo struct {
+	Move      int // Moves played to reach the position shown
+	Total     int
+	LastMove  game.Move
+	LastMover game.PlayerColor
+	Playing   bool // Whether the viewer is auto-playing
+}
+
+// renderBoard displays the column markers and the grid with its mice
+func (r *TerminalRenderer) renderBoard(state game.GameState) {
 	// Print column indicators with validity markers
+	hint := r.hintFor(state)
 	fmt.Print("  ")
-	for col := 0; col < game.GridWidth; col++ {
-		if col == state.SelectedColumn {
+	for col := 0; col < state.Config.Width; col++ {
+		if hint != nil && col == hint.Column {
+			if hint.Direction == game.DirectionUp {
+				fmt.Print("⏫") // Suggested column, to shift up
+			} else {
+				fmt.Print("⏬") // Suggested column, to shift down
+			}
+		} else if col == state.SelectedColumn {
 			if r.game.CanPlayerMoveColumn(state.CurrentPlayer, col) {
 				fmt.Print("🔽") // Valid selected column
 			} else {
 				fmt.Print("❌") // Invalid selected column
 			}
 		} else {
-			if r.game.CanPlayerMoveColumn(state.CurrentPlayer, col) {
+			if r.game.CanPlayerMoveColumn(state.CurrentPlayer, col) && col == state.LastShiftColumn {
+				fmt.Print(arrowFor(state.LastShiftDirection) + " ") // Valid only the way it was just shifted
+			} else if r.game.CanPlayerMoveColumn(state.CurrentPlayer, col) {
 				fmt.Print("✓ ") // Valid column
 			} else {
 				fmt.Print("  ") // Invalid/empty column
@@ -49,20 +150,71 @@ func (r *TerminalRenderer) Render(state game.GameState) {
 		}
 	}
 	fmt.Println()
+	r.renderAnalysis(state)
 
 	// Print the grid with mice
-	for row := 0; row < game.GridHeight; row++ {
+	for row := 0; row < state.Config.Height; row++ {
 		fmt.Print("  ")
-		for col := 0; col < game.GridWidth; col++ {
+		for col := 0; col < state.Config.Width; col++ {
 			cell := r.getCellDisplay(state, game.Position{Row: row, Col: col})
 			fmt.Print(cell)
 		}
 		fmt.Println()
 	}
+}
 
-	r.showPlayerStats(state)
-	r.showTurnInfo(state)
-	r.showControls()
+// renderAnalysis shows the score of shifting each column up and down, in mice, under the
+// column indicators, with the best move in reverse video. W and L are forced wins and
+// losses found by the search.
+func (r *TerminalRenderer) renderAnalysis(state game.GameState) {
+	if r.analysis == nil {
+		return
+	}
+	if r.analysis.Hash != state.Hash || r.analysis.Scores == nil {
+		fmt.Println("  🔍 Analysing...")
+		fmt.Println()
+		return
+	}
+
+	best := r.analysis.best()
+	for _, dir := range []game.Direction{game.DirectionUp, game.DirectionDown} {
+		if dir == game.DirectionUp {
+			fmt.Print("↑ ")
+		} else {
+			fmt.Print("↓ ")
+		}
+
+		cells := make([]string, state.Config.Width)
+		for col := range cells {
+			cells[col] = "  "
+		}
+		for _, score := range r.analysis.Scores {
+			if score.Move.Direction != dir {
+				continue
+			}
+			cell := scoreCell(score)
+			if score.Move == best.Move {
+				cell = "\033[7m" + cell + "\033[0m"
+			}
+			cells[score.Move.Column] = cell
+		}
+		for _, cell := range cells {
+			fmt.Print(cell)
+		}
+		fmt.Println()
+	}
+}
+
+// scoreCell formats a move score to fit a two-character board column
+func scoreCell(score ai.MoveScore) string {
+	switch score.Forced() {
+	case 1:
+		return "W "
+	case -1:
+		return "L "
+	}
+	mice := int(math.Round(score.Mice()))
+	return fmt.Sprintf("%+d", max(-9, min(9, mice)))
 }
 
 // getCellDisplay returns the appropriate emoji for a cell
@@ -161,10 +313,10 @@ func (r *TerminalRenderer) showPlayerStats(state game.GameState) {
 	bluePlayer := r.getPlayerInfo(state.Mice, game.Blue)
 
 	fmt.Printf("\nPlayer Stats:\n")
-	fmt.Printf("🔺 Red:  %d mice | Valid columns: %s\n",
-		len(redPlayer), r.getValidColumnsDisplay(game.Red))
-	fmt.Printf("🔹 Blue: %d mice | Valid columns: %s\n",
-		len(bluePlayer), r.getValidColumnsDisplay(game.Blue))
+	fmt.Printf("🔺 Red:  %d mice | Home: %d | Valid columns: %s\n",
+		len(redPlayer), state.Escaped[game.Red], r.getValidColumnsDisplay(game.Red))
+	fmt.Printf("🔹 Blue: %d mice | Home: %d | Valid columns: %s\n",
+		len(bluePlayer), state.Escaped[game.Blue], r.getValidColumnsDisplay(game.Blue))
 }
 
 // getValidColumnsDisplay returns a display string for valid columns
@@ -195,13 +347,50 @@ func (r *TerminalRenderer) getPlayerInfo(mice []game.Mouse, color game.PlayerCol
 	return result
 }
 
+// showHistory displays how many moves can be taken back and replayed
+func (r *TerminalRenderer) showHistory() {
+	undo, redo := r.game.HistoryDepth()
+	if undo == 0 && redo == 0 {
+		return
+	}
+	fmt.Printf("↩️  History: %d move(s) to undo, %d to redo\n", undo, redo)
+}
+
 // showTurnInfo displays turn-specific information
 func (r *TerminalRenderer) showTurnInfo(state game.GameState) {
 	fmt.Printf("\nTurn Info:\n")
 
+	// Show the opponent's last move, since that column can't be shifted back this turn
+	if state.LastShiftColumn >= 0 {
+		arrow := "↑"
+		if state.LastShiftDirection == game.DirectionDown {
+			arrow = "↓"
+		}
+		fmt.Printf("↪️  %s shifted column %d %s\n",
+			state.CurrentPlayer.Opponent().String(), state.LastShiftColumn+1, arrow)
+	}
+
+	if hint := r.hintFor(state); hint != nil {
+		fmt.Printf("💡 Hint: shift column %d %s\n", hint.Column+1, arrowFor(hint.Direction))
+	}
+
+	if a := r.analysis; a != nil && a.Hash == state.Hash && len(a.Scores) > 0 {
+		best := a.best()
+		fmt.Printf("🔍 Best move at depth %d: column %d %s (%s)\n",
+			a.Depth, best.Move.Column+1, arrowFor(best.Move.Direction), describeScore(best))
+	}
+
 	// Check if current selection is valid
 	isValidSelection := r.game.CanPlayerMoveColumn(state.CurrentPlayer, state.SelectedColumn)
-	if isValidSelection {
+	if isValidSelection && state.SelectedColumn == state.LastShiftColumn {
+		fmt.Printf("🔒 Column %d was just shifted %s by %s and can't be moved back\n",
+			state.SelectedColumn+1, arrowFor(state.LastShiftDirection), state.CurrentPlayer.Opponent().String())
+		if state.LastShiftDirection == game.DirectionDown {
+			fmt.Println("   Use ↓ (or S or J) to shift it further down")
+		} else {
+			fmt.Println("   Use ↑ (or W or K) to shift it further up")
+		}
+	} else if isValidSelection {
 		fmt.Printf("✅ Column %d is ready to move!\n", state.SelectedColumn+1)
 		fmt.Println("   Use ↑/↓ (or W/S or K/J) to move this column")
 	} else {
@@ -210,6 +399,25 @@ func (r *TerminalRenderer) showTurnInfo(state game.GameState) {
 	}
 }
 
+// arrowFor returns the arrow for a shift direction
+func arrowFor(dir game.Direction) string {
+	if dir == game.DirectionDown {
+		return "↓"
+	}
+	return "↑"
+}
+
+// describeScore explains a move score in words
+func describeScore(score ai.MoveScore) string {
+	switch score.Forced() {
+	case 1:
+		return "forced win"
+	case -1:
+		return "forced loss"
+	}
+	return fmt.Sprintf("%+.2f mice", score.Mice())
+}
+
 // ShowMessage displays a message to the user
 func (r *TerminalRenderer) ShowMessage(msg string) {
 	fmt.Println(msg)
@@ -220,10 +428,24 @@ func (r *TerminalRenderer) showControls() {
 	fmt.Println("\nControls:")
 	fmt.Println("← → (or A/D or H/L) : Select column with your mice")
 	fmt.Println("↑ ↓ (or W/S or K/J)  : Move your column up/down")
+	fmt.Println("u / r                : Undo / redo a move")
+	fmt.Println("?                    : Hint")
+	fmt.Println("e                    : Analysis overlay on / off")
 	fmt.Println("q                    : Quit")
 	fmt.Println("\nLegend:")
 	fmt.Println("🔺 Red mice    🔹 Blue mice    🟠 Mixed")
 	fmt.Println("🟫 Wall        ⬛ Empty        ✓ Valid column")
+	fmt.Println("⏫ ⏬ Hint: shift this column up / down")
+	fmt.Println("↑ ↓ Column your opponent just shifted: you can shift it on that way, but not straight back")
+}
+
+// showReplayControls displays the replay viewer's control instructions
+func (r *TerminalRenderer) showReplayControls() {
+	fmt.Println("\nControls:")
+	fmt.Println("← → (or A/D or H/L)    : Step back / forward one move")
+	fmt.Println("Home End (or ↑ ↓, g G) : Jump to the start / end")
+	fmt.Println("Space (or P)           : Play / pause")
+	fmt.Println("q                      : Quit")
 }
 
 // HideCursor hides the terminal cursor
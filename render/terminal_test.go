@@ -0,0 +1,36 @@
+package render
+
+import (
+	"testing"
+
+	"micemen/game"
+)
+
+func TestHintOnlyShowsForItsPosition(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 1)
+	r := NewTerminalRenderer(g)
+	state := g.GetState()
+	if r.hintFor(state) != nil {
+		t.Fatalf("A new renderer shouldn't have a hint")
+	}
+	
+	move := game.LegalMoves(state)[0]
+	r.SetHint(state, move)
+	if hint := r.hintFor(state); hint == nil || *hint != move {
+		t.Errorf("Hint for the position should be %v, got %v", move, hint)
+	}
+	
+	// Once the position changes the hint no longer applies
+	if err := g.ApplyMove(move); err != nil {
+		t.Fatalf("ApplyMove failed: %v", err)
+	}
+	if hint := r.hintFor(g.GetState()); hint != nil {
+		t.Errorf("Hint shouldn't show after a move, got %v", *hint)
+	}
+	
+	// Taking the move back brings the same position, and its hint, back
+	g.Undo()
+	if hint := r.hintFor(g.GetState()); hint == nil || *hint != move {
+		t.Errorf("Hint should show again after undoing the move, got %v", hint)
+	}
+}
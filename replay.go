@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"micemen/game"
+	"micemen/input"
+	"micemen/record"
+	"micemen/render"
+)
+
+// ReplayViewer steps through a recorded game, moving along its undo history
+type ReplayViewer struct {
+	game   *game.MicemenGame
+	render *render.TerminalRenderer
+	input  *input.ReplayKeyboardHandler
+
+	moves   []game.Move
+	movers  []game.PlayerColor // Who played each move
+	delay   time.Duration      // Time between moves when auto-playing
+	playing bool
+}
+
+// NewReplayViewer creates a viewer for a replayed game, starting at its first position
+func NewReplayViewer(g *game.MicemenGame, moves []game.Move, delay time.Duration) *ReplayViewer {
+	// Rewind to the start; each undo reveals who played the move taken back
+	movers := make([]game.PlayerColor, len(moves))
+	for i := len(moves) - 1; g.Undo(); i-- {
+		movers[i] = g.GetState().CurrentPlayer
+	}
+
+	return &ReplayViewer{
+		game:   g,
+		render: render.NewTerminalRenderer(g),
+		input:  input.NewReplayKeyboardHandler(),
+		moves:  moves,
+		movers: movers,
+		delay:  delay,
+	}
+}
+
+// Run shows the replay until the viewer is closed
+func (v *ReplayViewer) Run() error {
+	if err := v.input.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize input: %w", err)
+	}
+	defer v.input.Close()
+
+	v.render.HideCursor()
+	defer v.render.ShowCursor()
+
+	for {
+		v.render.RenderReplay(v.game.GetState(), v.info())
+
+		var action game.Action
+		var err error
+		if v.playing {
+			action, err = v.input.WaitForAction(v.delay)
+			if err == nil && action == game.ActionNone {
+				action = game.ActionStepForward
+			}
+		} else {
+			action, err = v.input.GetNextAction()
+		}
+		if err != nil {
+			return fmt.Errorf("input error: %w", err)
+		}
+		if action == game.ActionQuit {
+			return nil
+		}
+		v.handle(action)
+	}
+}
+
+// handle steps through the game for a viewer action, and stops auto-play at the end
+func (v *ReplayViewer) handle(action game.Action) {
+	switch action {
+	case game.ActionStepBack:
+		v.playing = false
+		v.game.Undo()
+	case game.ActionStepForward:
+		v.game.Redo()
+	case game.ActionJumpToStart:
+		v.playing = false
+		for v.game.Undo() {
+		}
+	case game.ActionJumpToEnd:
+		for v.game.Redo() {
+		}
+	case game.ActionTogglePlay:
+		// Playing from the end starts over
+		if _, redo := v.game.HistoryDepth(); !v.playing && redo == 0 {
+			for v.game.Undo() {
+			}
+		}
+		v.playing = !v.playing
+	}
+
+	if _, redo := v.game.HistoryDepth(); redo == 0 {
+		v.playing = false
+	}
+}
+
+// info describes the position shown for the replay header
+func (v *ReplayViewer) info() render.ReplayInfo {
+	move, _ := v.game.HistoryDepth()
+	info := render.ReplayInfo{Move: move, Total: len(v.moves), Playing: v.playing}
+	if move > 0 {
+		info.LastMove = v.moves[move-1]
+		info.LastMover = v.movers[move-1]
+	}
+	return info
+}
+
+// runReplay handles "micemen replay [flags] <file>": step through a recorded game
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	delay := fs.Duration("delay", time.Second, "time between moves when auto-playing")
+	autoplay := fs.Bool("play", false, "start playing the game straight away")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: micemen replay [flags] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || *delay <= 0 {
+		fs.Usage()
+		return errUsage
+	}
+	path := fs.Arg(0)
+
+	rec, err := record.Load(path)
+	if err != nil {
+		return err
+	}
+	g, err := rec.Replay()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	final := g.GetState()
+
+	viewer := NewReplayViewer(g, rec.Moves, *delay)
+	viewer.playing = *autoplay && len(rec.Moves) > 0
+	if err := viewer.Run(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s (%s): %s vs %s, %d moves, result %s\n",
+		rec.Event, rec.Date, rec.Red, rec.Blue, len(rec.Moves), rec.Result)
+	if final.Result == game.ResultAllMiceHome && final.Winner.String() != rec.Result {
+		fmt.Printf("⚠️  The record says %s, but replaying it gives %s\n", rec.Result, final.Winner)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"micemen/game"
+)
+
+// newTestViewer plays a few moves of a board and opens a viewer on them
+func newTestViewer(t *testing.T, moves int) *ReplayViewer {
+	t.Helper()
+	g := game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 5)
+	for i := 0; i < moves; i++ {
+		if err := g.ApplyMove(game.LegalMoves(g.GetState())[0]); err != nil {
+			t.Fatalf("ApplyMove failed: %v", err)
+		}
+	}
+	return NewReplayViewer(g, g.Moves(), time.Second)
+}
+
+func TestReplayViewerSteps(t *testing.T) {
+	v := newTestViewer(t, 3)
+	if info := v.info(); info.Move != 0 || info.Total != 3 {
+		t.Fatalf("Viewer should start at move 0 of 3, got %d of %d", info.Move, info.Total)
+	}
+	
+	steps := []struct {
+		action game.Action
+		want   int
+	}{
+		{game.ActionStepBack, 0},
+		{game.ActionStepForward, 1},
+		{game.ActionStepForward, 2},
+		{game.ActionStepBack, 1},
+		{game.ActionJumpToEnd, 3},
+		{game.ActionStepForward, 3},
+		{game.ActionJumpToStart, 0},
+	}
+	for i, step := range steps {
+		v.handle(step.action)
+		if info := v.info(); info.Move != step.want {
+			t.Errorf("Step %d should show move %d, got %d", i, step.want, info.Move)
+		}
+	}
+	
+	v.handle(game.ActionStepForward)
+	info := v.info()
+	if info.LastMove != v.moves[0] || info.LastMover != game.Red {
+		t.Errorf("After the first move the header should show Red's %v, got %s's %v", v.moves[0], info.LastMover, info.LastMove)
+	}
+}
+
+func TestReplayViewerPlay(t *testing.T) {
+	v := newTestViewer(t, 2)
+	
+	v.handle(game.ActionTogglePlay)
+	if !v.playing {
+		t.Fatalf("Play should start auto-play")
+	}
+	v.handle(game.ActionStepBack)
+	if v.playing {
+		t.Errorf("Stepping back should pause auto-play")
+	}
+	
+	// Auto-play stops by itself at the end of the game
+	v.handle(game.ActionTogglePlay)
+	v.handle(game.ActionStepForward)
+	v.handle(game.ActionStepForward)
+	if v.playing || v.info().Move != 2 {
+		t.Errorf("Auto-play should stop at the last move, got move %d (playing %v)", v.info().Move, v.playing)
+	}
+	
+	// Playing from the end starts over from the first position
+	v.handle(game.ActionTogglePlay)
+	if !v.playing || v.info().Move != 0 {
+		t.Errorf("Play at the end should restart from move 0, got move %d (playing %v)", v.info().Move, v.playing)
+	}
+}
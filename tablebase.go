@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"micemen/game"
+	"micemen/tablebase"
+)
+
+// runTablebase handles "micemen tablebase solve|probe": endgame table maintenance
+func runTablebase(args []string) error {
+	if len(args) == 0 || args[0] != "solve" && args[0] != "probe" {
+		fmt.Fprintln(os.Stderr, "Usage: micemen tablebase solve [flags] <file>")
+		fmt.Fprintln(os.Stderr, "       micemen tablebase probe [flags] <file>")
+		return errUsage
+	}
+	command := args[0]
+
+	fs := flag.NewFlagSet("tablebase "+command, flag.ExitOnError)
+	opts := addGameFlags(fs)
+	limit := fs.Int("limit", tablebase.DefaultLimit, "most positions to visit while solving")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: micemen tablebase %s [flags] <file>\n", command)
+		fmt.Fprintf(fs.Output(), "The board, from -seed or -map, must have at most %d mice a side.\n", tablebase.MaxMice)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args[1:])
+	if fs.NArg() != 1 || *limit < 1 {
+		fs.Usage()
+		return errUsage
+	}
+	path := fs.Arg(0)
+
+	g, err := opts.newGame()
+	if err != nil {
+		return err
+	}
+	state := g.GetState()
+
+	if command == "solve" {
+		return solveEndgame(path, state, *limit)
+	}
+	return probeEndgame(path, state)
+}
+
+// solveEndgame solves the board's endgame and adds it to the table file, creating the
+// file if it doesn't exist yet
+func solveEndgame(path string, state game.GameState, limit int) error {
+	table, err := tablebase.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		table, err = tablebase.NewTable(), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	solved, err := tablebase.Solve(state, limit)
+	if err != nil {
+		return err
+	}
+	before := table.Len()
+	table.Merge(solved)
+	if err := tablebase.Save(path, table); err != nil {
+		return fmt.Errorf("saving tablebase: %w", err)
+	}
+
+	result, _ := solved.Probe(state)
+	fmt.Printf("Solved %d positions, %d of them new: %s.\n", solved.Len(), table.Len()-before, result.Describe(state.CurrentPlayer))
+	fmt.Printf("%s now holds %d positions.\n", path, table.Len())
+	return nil
+}
+
+// probeEndgame shows what the table knows about the board's position and each move
+func probeEndgame(path string, state game.GameState) error {
+	table, err := tablebase.Load(path)
+	if err != nil {
+		return err
+	}
+
+	result, ok := table.Probe(state)
+	if !ok {
+		fmt.Println("The tablebase doesn't have this position.")
+		return nil
+	}
+	fmt.Printf("%s to move: %s\n", state.CurrentPlayer, result.Describe(state.CurrentPlayer))
+
+	best, _, ok := table.BestMove(state)
+	if !ok {
+		return nil
+	}
+	for _, move := range game.LegalMoves(state) {
+		child, err := game.Apply(state, move)
+		if err != nil {
+			return err
+		}
+		outcome := fmt.Sprintf("%s wins", child.Winner)
+		if !child.GameOver {
+			reply, _ := table.Probe(child)
+			outcome = reply.Describe(child.CurrentPlayer)
+		}
+
+		marker := " "
+		if move == best {
+			marker = "*"
+		}
+		fmt.Printf(" %s %-4s %s\n", marker, move, outcome)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package tablebase
+
+import (
+	"micemen/ai"
+	"micemen/game"
+)
+
+// Solver answers endgame queries from a table, solving endgames the table doesn't cover
+// yet and adding them to it. It isn't safe for concurrent use, but it can run one solve
+// in the background for callers that mustn't wait, such as a UI.
+type Solver struct {
+	Table *Table
+	Limit int // Positions a solve may visit
+
+	failedMice int              // Mice on the board when a solve last ran out of positions, or 0
+	solving    bool             // Whether a background solve is under way
+	done       chan solveResult // Hands over the background solve's result
+}
+
+// solveResult is what a background solve found
+type solveResult struct {
+	table *Table
+	err   error
+	mice  int // Mice on the board of the position solved
+}
+
+// NewSolver creates a solver that starts from the given table
+func NewSolver(t *Table) *Solver {
+	return &Solver{Table: t, Limit: DefaultLimit, done: make(chan solveResult, 1)}
+}
+
+// ProbeInBackground returns the result of an endgame position if the table has it. If not,
+// it starts solving the position in the background, unless a solve is already under way,
+// and Collect adds the result to the table once it's done.
+func (s *Solver) ProbeInBackground(state game.GameState) (Result, bool) {
+	if !IsEndgame(state) {
+		return Result{}, false
+	}
+	if result, ok := s.Table.Probe(state); ok {
+		return result, true
+	}
+	if s.solving || s.failedMice > 0 && len(state.Mice) >= s.failedMice {
+		return Result{}, false
+	}
+
+	s.solving = true
+	state, limit := state.Clone(), s.Limit
+	go func() {
+		table, err := Solve(state, limit)
+		s.done <- solveResult{table: table, err: err, mice: len(state.Mice)}
+	}()
+	return Result{}, false
+}
+
+// Solving reports whether a background solve is under way
+func (s *Solver) Solving() bool {
+	return s.solving
+}
+
+// Collect adds the result of a finished background solve to the table, and reports
+// whether there was one
+func (s *Solver) Collect() bool {
+	if !s.solving {
+		return false
+	}
+	select {
+	case result := <-s.done:
+		s.solving = false
+		if result.err != nil {
+			s.failedMice = result.mice
+		} else {
+			s.Table.Merge(result.table)
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Probe returns the result of an endgame position for the player to move
+func (s *Solver) Probe(state game.GameState) (Result, bool) {
+	if !s.cover(state) {
+		return Result{}, false
+	}
+	return s.Table.Probe(state)
+}
+
+// BestMove returns a perfect move in an endgame position and the result it keeps
+func (s *Solver) BestMove(state game.GameState) (game.Move, Result, bool) {
+	if !s.cover(state) {
+		return game.Move{}, Result{}, false
+	}
+	return s.Table.BestMove(state)
+}
+
+// cover makes sure the table has the position, solving it if it's an endgame. An endgame
+// too big to solve isn't tried again until a mouse has left the board.
+func (s *Solver) cover(state game.GameState) bool {
+	if !IsEndgame(state) {
+		return false
+	}
+	s.Collect()
+	if _, ok := s.Table.Probe(state); ok {
+		return true
+	}
+	if s.failedMice > 0 && len(state.Mice) >= s.failedMice {
+		return false
+	}
+
+	solved, err := Solve(state, s.Limit)
+	if err != nil {
+		s.failedMice = len(state.Mice)
+		return false
+	}
+	s.Table.Merge(solved)
+	return true
+}
+
+// Bot plays endgames perfectly and lets another bot choose the rest of the time
+type Bot struct {
+	Solver   *Solver
+	Fallback ai.Bot
+}
+
+// NewBot wraps a bot so it plays perfect endgames
+func NewBot(s *Solver, fallback ai.Bot) *Bot {
+	return &Bot{Solver: s, Fallback: fallback}
+}
+
+// Name returns the fallback bot's name marked as using the tablebase
+func (b *Bot) Name() string {
+	return b.Fallback.Name() + "+tablebase"
+}
+
+// ChooseMove plays the tablebase move in an endgame, or asks the fallback bot
+func (b *Bot) ChooseMove(g game.Game) (game.Move, error) {
+	if move, _, ok := b.Solver.BestMove(g.GetState()); ok {
+		return move, nil
+	}
+	return b.Fallback.ChooseMove(g)
+}
@@ -0,0 +1,239 @@
+// Package tablebase solves endgames exactly: once each side has only a couple of mice
+// left, every position the game can reach is enumerated and worked back from the won
+// ones, giving perfect play and the exact number of moves to the end
+package tablebase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"micemen/game"
+)
+
+// Solver settings
+const (
+	MaxMice      = 2       // Endgames have at most this many mice per side on the board
+	DefaultLimit = 1 << 18 // Positions a solve may visit before giving up; memory use grows with it and the board size
+)
+
+// ErrTooManyPositions is returned when an endgame has more positions than the solve limit
+var ErrTooManyPositions = errors.New("too many positions")
+
+// Terminal children are stored as negative indices
+const (
+	moverWins    = -1 // The move ends the game won by the player who made it
+	opponentWins = -2 // The move ends the game won by the other player
+)
+
+// IsEndgame reports whether a position is small enough to solve: a game in progress with
+// at most MaxMice mice per side on the board
+func IsEndgame(state game.GameState) bool {
+	if state.GameOver {
+		return false
+	}
+	var mice [2]int
+	for _, mouse := range state.Mice {
+		mice[mouse.Player]++
+	}
+	return mice[game.Red] <= MaxMice && mice[game.Blue] <= MaxMice
+}
+
+// Key reduces a position to what still affects play: its Zobrist hash without the walls
+// of columns no mouse can reach again. Red mice only walk right and Blue mice left, and
+// players only shift columns holding their own mice, so once every Red mouse is past a
+// column and every Blue mouse is short of it, that column can never matter. Ignoring it
+// keeps endgames where the mice have passed each other small enough to solve.
+//
+// The hash leaves out the mice home, which a game's mice per player already fix, so the
+// key adds them back: one table can hold endgames of boards with different mice counts.
+func Key(state game.GameState) uint64 {
+	redFrom, blueTo := state.Config.Width, -1
+	for _, mouse := range state.Mice {
+		if mouse.Player == game.Red {
+			redFrom = min(redFrom, mouse.Position.Col)
+		} else {
+			blueTo = max(blueTo, mouse.Position.Col)
+		}
+	}
+
+	key := state.Hash ^ escapedKey(state.Escaped)
+	for col := blueTo + 1; col < redFrom; col++ {
+		key ^= state.ColumnWallsHash(col)
+	}
+	return key
+}
+
+// escapedKey spreads the number of mice home on each side over 64 bits with the
+// splitmix64 finalizer, so keys stay the same from one run to the next
+func escapedKey(escaped [2]int) uint64 {
+	x := uint64(escaped[game.Red])<<32 | uint64(escaped[game.Blue])
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// Solve works out every position reachable from an endgame position, by Key. It fails
+// with ErrTooManyPositions rather than visit more than limit positions.
+func Solve(root game.GameState, limit int) (*Table, error) {
+	if !IsEndgame(root) {
+		return nil, fmt.Errorf("not an endgame: solving needs at most %d mice per side and a game in progress", MaxMice)
+	}
+
+	graph, err := explore(root, limit)
+	if err != nil {
+		return nil, err
+	}
+	values := graph.retrograde()
+
+	// Store the positions in hash order for lookup by binary search
+	order := make([]int32, len(graph.hashes))
+	for i := range order {
+		order[i] = int32(i)
+	}
+	slices.SortFunc(order, func(a, b int32) int {
+		switch x, y := graph.hashes[a], graph.hashes[b]; {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	})
+
+	t := &Table{hashes: make([]uint64, len(order)), values: make([]int16, len(order))}
+	for i, pos := range order {
+		t.hashes[i] = graph.hashes[pos]
+		t.values[i] = values[pos]
+	}
+	return t, nil
+}
+
+// positionGraph is every position reachable from the root and the moves between them,
+// with each position's children stored from childStart[i] to childStart[i+1]
+type positionGraph struct {
+	hashes     []uint64 // Key of each position
+	childStart []int32
+	children   []int32 // Position index, or moverWins or opponentWins for a finished game
+}
+
+// explore enumerates the positions reachable from the root, breadth first
+func explore(root game.GameState, limit int) (*positionGraph, error) {
+	graph := &positionGraph{}
+	index := map[uint64]int32{Key(root): 0}
+	graph.hashes = append(graph.hashes, Key(root))
+
+	queue := []game.GameState{root}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue[0] = game.GameState{}
+		queue = queue[1:]
+
+		graph.childStart = append(graph.childStart, int32(len(graph.children)))
+		for _, move := range game.LegalMoves(state) {
+			child, err := game.Apply(state, move)
+			if err != nil {
+				return nil, err
+			}
+
+			if child.GameOver {
+				if child.Winner == state.CurrentPlayer {
+					graph.children = append(graph.children, moverWins)
+				} else {
+					graph.children = append(graph.children, opponentWins)
+				}
+				continue
+			}
+
+			key := Key(child)
+			i, ok := index[key]
+			if !ok {
+				if len(graph.hashes) >= limit {
+					return nil, fmt.Errorf("%w: the endgame has more than %d", ErrTooManyPositions, limit)
+				}
+				i = int32(len(graph.hashes))
+				index[key] = i
+				graph.hashes = append(graph.hashes, key)
+				queue = append(queue, child)
+			}
+			graph.children = append(graph.children, i)
+		}
+	}
+	graph.childStart = append(graph.childStart, int32(len(graph.children)))
+	return graph, nil
+}
+
+// retrograde values every position for the player to move, working back from the moves
+// that end the game: a position is won if some move leads to a lost position and lost
+// once every move leads to a won one. Positions are settled in order of distance from the
+// end, so each gets the shortest win or the longest loss. Whatever is left is a draw.
+func (g *positionGraph) retrograde() []int16 {
+	n := len(g.hashes)
+	values := make([]int16, n)
+	unsolved := make([]int32, n) // Moves not yet known to lose, for positions not settled
+	queue := make([]int32, 0, n)
+
+	// Predecessor lists, built the same way as the child lists
+	predStart := make([]int32, n+1)
+	for _, child := range g.children {
+		if child >= 0 {
+			predStart[child+1]++
+		}
+	}
+	for i := 1; i <= n; i++ {
+		predStart[i] += predStart[i-1]
+	}
+	preds := make([]int32, predStart[n])
+	fill := slices.Clone(predStart[:n])
+	for pos := range n {
+		for _, child := range g.children[g.childStart[pos]:g.childStart[pos+1]] {
+			if child >= 0 {
+				preds[fill[child]] = int32(pos)
+				fill[child]++
+			}
+		}
+	}
+
+	// Positions one move from the end
+	for pos := range n {
+		children := g.children[g.childStart[pos]:g.childStart[pos+1]]
+		unsolved[pos] = int32(len(children))
+		for _, child := range children {
+			switch child {
+			case moverWins:
+				values[pos] = 1
+			case opponentWins:
+				unsolved[pos]--
+			}
+		}
+		if values[pos] == 0 && unsolved[pos] == 0 {
+			values[pos] = -1
+		}
+		if values[pos] != 0 {
+			queue = append(queue, int32(pos))
+		}
+	}
+
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		value := values[pos]
+
+		for _, pred := range preds[predStart[pos]:predStart[pos+1]] {
+			if values[pred] != 0 {
+				continue
+			}
+			if value < 0 {
+				// Moving here leaves the opponent lost
+				values[pred] = -value + 1
+				queue = append(queue, pred)
+			} else if unsolved[pred]--; unsolved[pred] == 0 {
+				// Every move leaves the opponent winning, this one the slowest
+				values[pred] = -(value + 1)
+				queue = append(queue, pred)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,281 @@
+package tablebase
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"micemen/game"
+)
+
+// A table file is little-endian binary: the magic "MMTB", a uint16 format version and a
+// uint64 position count, then the sorted position keys as uint64s and their values as
+// int16s. A value is the number of moves left to the end of the game with perfect play,
+// counting both sides' moves: positive when the player to move wins, negative when they
+// lose and 0 for a draw.
+
+// tableMagic starts every table file
+const tableMagic = "MMTB"
+
+// tableVersion is the file format version written by Write
+const tableVersion = 1
+
+// entrySize is the bytes each position takes in a table file: its key and its value
+const entrySize = 8 + 2
+
+// Outcome is how a position ends with perfect play, for the player to move
+type Outcome int
+
+const (
+	Draw Outcome = iota
+	Win
+	Loss
+)
+
+// Result is the solved value of a position
+type Result struct {
+	Outcome Outcome
+	Plies   int // Moves by both sides until the game ends; 0 for a draw
+}
+
+// resultOf decodes a stored value
+func resultOf(value int16) Result {
+	switch {
+	case value > 0:
+		return Result{Outcome: Win, Plies: int(value)}
+	case value < 0:
+		return Result{Outcome: Loss, Plies: int(-value)}
+	default:
+		return Result{Outcome: Draw}
+	}
+}
+
+// Mate returns how many moves the winner needs to finish the game, not counting the
+// loser's. A loss in 1 is 0: whatever the loser plays wins the game for the other side.
+func (r Result) Mate() int {
+	if r.Outcome == Win {
+		return (r.Plies + 1) / 2
+	}
+	return r.Plies / 2
+}
+
+// Describe explains the result for the given player to move
+func (r Result) Describe(toMove game.PlayerColor) string {
+	winner := toMove
+	switch r.Outcome {
+	case Draw:
+		return "Drawn with best play"
+	case Loss:
+		winner = toMove.Opponent()
+	}
+
+	if n := r.Mate(); n > 0 {
+		return fmt.Sprintf("%s mates in %d", winner, n)
+	}
+	return fmt.Sprintf("%s wins whatever %s plays", winner, toMove)
+}
+
+// Table holds solved positions, sorted by hash
+type Table struct {
+	hashes []uint64
+	values []int16
+}
+
+// NewTable creates an empty table
+func NewTable() *Table {
+	return &Table{}
+}
+
+// Len returns the number of positions in the table
+func (t *Table) Len() int {
+	return len(t.hashes)
+}
+
+// Lookup returns the result stored for a position Key
+func (t *Table) Lookup(hash uint64) (Result, bool) {
+	i, ok := slices.BinarySearch(t.hashes, hash)
+	if !ok {
+		return Result{}, false
+	}
+	return resultOf(t.values[i]), true
+}
+
+// Probe returns the result of a position for the player to move, if the table has it
+func (t *Table) Probe(state game.GameState) (Result, bool) {
+	if state.GameOver {
+		return Result{}, false
+	}
+	return t.Lookup(Key(state))
+}
+
+// BestMove returns a move that keeps the best result for the player to move: the fastest
+// win, else a draw, else the slowest loss. It needs every move's result in the table.
+func (t *Table) BestMove(state game.GameState) (game.Move, Result, bool) {
+	var best game.Move
+	var bestResult Result
+	found := false
+
+	for _, move := range game.LegalMoves(state) {
+		child, err := game.Apply(state, move)
+		if err != nil {
+			return game.Move{}, Result{}, false
+		}
+
+		var result Result
+		switch {
+		case child.GameOver && child.Winner == state.CurrentPlayer:
+			result = Result{Outcome: Win, Plies: 1}
+		case child.GameOver:
+			result = Result{Outcome: Loss, Plies: 1}
+		default:
+			reply, ok := t.Lookup(Key(child))
+			if !ok {
+				return game.Move{}, Result{}, false
+			}
+			result = after(reply)
+		}
+
+		if !found || better(result, bestResult) {
+			best, bestResult, found = move, result, true
+		}
+	}
+	return best, bestResult, found
+}
+
+// after turns the result of the position after a move, for the opponent, into the result
+// of the move for the player making it
+func after(reply Result) Result {
+	switch reply.Outcome {
+	case Win:
+		return Result{Outcome: Loss, Plies: reply.Plies + 1}
+	case Loss:
+		return Result{Outcome: Win, Plies: reply.Plies + 1}
+	default:
+		return reply
+	}
+}
+
+// better reports whether a result is better than another for the player it belongs to
+func better(a, b Result) bool {
+	rank := func(r Result) int {
+		switch r.Outcome {
+		case Win:
+			return 1<<20 - r.Plies
+		case Loss:
+			return -(1 << 20) + r.Plies
+		default:
+			return 0
+		}
+	}
+	return rank(a) > rank(b)
+}
+
+// Merge adds the positions of another table that this one doesn't have
+func (t *Table) Merge(other *Table) {
+	hashes := make([]uint64, 0, len(t.hashes)+len(other.hashes))
+	values := make([]int16, 0, cap(hashes))
+
+	i, j := 0, 0
+	for i < len(t.hashes) || j < len(other.hashes) {
+		switch {
+		case j == len(other.hashes) || i < len(t.hashes) && t.hashes[i] < other.hashes[j]:
+			hashes, values = append(hashes, t.hashes[i]), append(values, t.values[i])
+			i++
+		case i == len(t.hashes) || other.hashes[j] < t.hashes[i]:
+			hashes, values = append(hashes, other.hashes[j]), append(values, other.values[j])
+			j++
+		default:
+			hashes, values = append(hashes, t.hashes[i]), append(values, t.values[i])
+			i++
+			j++
+		}
+	}
+	t.hashes, t.values = hashes, values
+}
+
+// Load reads a table file from disk
+func Load(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Save writes a table file to disk
+func Save(path string, t *Table) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := Write(f, t); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes a table
+func Write(w io.Writer, t *Table) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(tableMagic)
+	binary.Write(bw, binary.LittleEndian, uint16(tableVersion))
+	binary.Write(bw, binary.LittleEndian, uint64(len(t.hashes)))
+	binary.Write(bw, binary.LittleEndian, t.hashes)
+	binary.Write(bw, binary.LittleEndian, t.values)
+	return bw.Flush()
+}
+
+// Read reads a table
+func Read(r io.Reader) (*Table, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(tableMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != tableMagic {
+		return nil, fmt.Errorf("not a tablebase file")
+	}
+	var version uint16
+	if err := binary.Read(br, binary.LittleEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != tableVersion {
+		return nil, fmt.Errorf("unsupported tablebase version %d", version)
+	}
+
+	var count uint64
+	if err := binary.Read(br, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+
+	// The count only sizes the table once the rest of the file is known to hold that many
+	// positions, so a corrupt header can't ask for more memory than the file takes
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(data))/entrySize || uint64(len(data)) != count*entrySize {
+		return nil, fmt.Errorf("header says %d positions but %d bytes of them follow", count, len(data))
+	}
+
+	t := &Table{hashes: make([]uint64, count), values: make([]int16, count)}
+	if _, err := binary.Decode(data, binary.LittleEndian, t.hashes); err != nil {
+		return nil, fmt.Errorf("reading positions: %w", err)
+	}
+	if _, err := binary.Decode(data[count*8:], binary.LittleEndian, t.values); err != nil {
+		return nil, fmt.Errorf("reading values: %w", err)
+	}
+	if !slices.IsSorted(t.hashes) {
+		return nil, fmt.Errorf("positions are out of order")
+	}
+	return t, nil
+}
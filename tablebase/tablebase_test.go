@@ -0,0 +1,246 @@
+package tablebase
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"micemen/ai"
+	"micemen/game"
+)
+
+// winInOneMap has Red one mouse from winning by shifting column 6 down
+const winInOneMap = "../testdata/win-in-one.map"
+
+// readMap returns the text of a map file, for tests that vary the position
+func readMap(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading %s failed: %v", path, err)
+	}
+	return string(data)
+}
+
+// loadState reads the position in a map
+func loadState(t *testing.T, text string) game.GameState {
+	t.Helper()
+	m, err := game.ReadMap(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ReadMap failed: %v", err)
+	}
+	return game.NewGameWithSeed(m.State.Config, game.MapGenerator{Map: m}, 0).GetState()
+}
+
+func TestSolveFindsWinInOne(t *testing.T) {
+	state := loadState(t, readMap(t, winInOneMap))
+	table, err := Solve(state, DefaultLimit)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	
+	result, ok := table.Probe(state)
+	if !ok || result != (Result{Outcome: Win, Plies: 1}) {
+		t.Errorf("Position should be a win in 1, got %+v (found %v)", result, ok)
+	}
+	if got := result.Describe(game.Red); got != "Red mates in 1" {
+		t.Errorf("Result should read \"Red mates in 1\", got %q", got)
+	}
+	want := game.Move{Column: 5, Direction: game.DirectionDown}
+	if move, _, ok := table.BestMove(state); !ok || move != want {
+		t.Errorf("Best move should be %v, got %v", want, move)
+	}
+}
+
+func TestPerfectPlayKeepsResult(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		state := game.NewGameWithSeed(game.NewConfig(9, 5, 1), game.UniformGenerator{}, seed).GetState()
+		table, err := Solve(state, DefaultLimit)
+		if err != nil {
+			t.Fatalf("Solve failed on board %d: %v", seed, err)
+		}
+		result, _ := table.Probe(state)
+		if result.Outcome == Draw {
+			continue
+		}
+		
+		// Both sides playing the table's moves should end the game as predicted
+		winner := state.CurrentPlayer
+		if result.Outcome == Loss {
+			winner = winner.Opponent()
+		}
+		plies := 0
+		for !state.GameOver {
+			move, _, ok := table.BestMove(state)
+			if !ok {
+				t.Fatalf("Board %d: table is missing a position reachable from the root", seed)
+			}
+			state, _ = game.Apply(state, move)
+			plies++
+		}
+		if state.Winner != winner || plies != result.Plies {
+			t.Errorf("Board %d should be won by %s in %d moves, but %s won in %d", seed, winner, result.Plies, state.Winner, plies)
+		}
+	}
+}
+
+func TestKeyIgnoresUnreachableColumns(t *testing.T) {
+	const passed = `micemen-map 1
+mice: 1
+
+.......
+.B...R.
+###.###
+`
+	const notPassed = `micemen-map 1
+mice: 1
+
+.......
+.R...B.
+###.###
+`
+	// Each map and the same map with a wall added in the middle column
+	for _, tc := range []struct {
+		name string
+		text string
+		same bool
+	}{{"mice past each other", passed, true}, {"mice still to meet", notPassed, false}} {
+		a := loadState(t, tc.text)
+		b := loadState(t, strings.Replace(tc.text, ".......", "...#...", 1))
+		if a.Hash == b.Hash {
+			t.Fatalf("%s: the wall should change the position hash", tc.name)
+		}
+		if same := Key(a) == Key(b); same != tc.same {
+			t.Errorf("%s: keys should match only if no mouse can reach the column (match %v)", tc.name, same)
+		}
+	}
+}
+
+func TestKeyCountsMiceHome(t *testing.T) {
+	// The same board with one more mouse per player, already home on both sides
+	a := loadState(t, readMap(t, winInOneMap))
+	b := loadState(t, strings.Replace(readMap(t, winInOneMap), "mice: 2\nhome: 1 0", "mice: 3\nhome: 2 1", 1))
+	if a.Hash != b.Hash {
+		t.Fatalf("Mice home shouldn't change the position hash")
+	}
+	if Key(a) == Key(b) {
+		t.Errorf("Boards with different numbers of mice home should have different keys")
+	}
+}
+
+func TestTableRoundTripAndMerge(t *testing.T) {
+	first, err := Solve(loadState(t, readMap(t, winInOneMap)), DefaultLimit)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	otherState := game.NewGameWithSeed(game.NewConfig(9, 5, 1), game.UniformGenerator{}, 2).GetState()
+	second, err := Solve(otherState, DefaultLimit)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	
+	merged := NewTable()
+	merged.Merge(first)
+	merged.Merge(second)
+	merged.Merge(first)
+	if merged.Len() != first.Len()+second.Len() {
+		t.Errorf("Merged table should have %d positions, got %d", first.Len()+second.Len(), merged.Len())
+	}
+	
+	var buf bytes.Buffer
+	if err := Write(&buf, merged); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if want := 4 + 2 + 8 + 10*merged.Len(); buf.Len() != want {
+		t.Errorf("Table file should take %d bytes, got %d", want, buf.Len())
+	}
+	loaded, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	for _, table := range []*Table{first, second} {
+		for i, hash := range table.hashes {
+			if got, ok := loaded.Lookup(hash); !ok || got != resultOf(table.values[i]) {
+				t.Fatalf("Loaded table should give %+v for %016x, got %+v", resultOf(table.values[i]), hash, got)
+			}
+		}
+	}
+	
+	if _, err := Read(strings.NewReader("not a table")); err == nil {
+		t.Errorf("Read should reject a file that isn't a table")
+	}
+	
+	// A header claiming more positions than follow is rejected before anything is allocated
+	var small bytes.Buffer
+	if err := Write(&small, first); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data := small.Bytes()
+	if _, err := Read(bytes.NewReader(data[:len(data)-1])); err == nil {
+		t.Errorf("Read should reject a truncated table")
+	}
+	huge := bytes.Clone(data)
+	copy(huge[6:14], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f})
+	if _, err := Read(bytes.NewReader(huge)); err == nil {
+		t.Errorf("Read should reject a position count larger than the file")
+	}
+}
+
+func TestSolveLimits(t *testing.T) {
+	if _, err := Solve(game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 1).GetState(), DefaultLimit); err == nil {
+		t.Errorf("Solve should refuse a position with more than %d mice a side", MaxMice)
+	}
+	
+	state := game.NewGameWithSeed(game.NewConfig(9, 5, 2), game.UniformGenerator{}, 3).GetState()
+	if _, err := Solve(state, 10); !errors.Is(err, ErrTooManyPositions) {
+		t.Errorf("Solve should stop at the position limit, got %v", err)
+	}
+}
+
+func TestBotPlaysEndgames(t *testing.T) {
+	bot := NewBot(NewSolver(NewTable()), ai.NewRandomBotWithSeed(1))
+	
+	g := game.NewGameFromState(loadState(t, readMap(t, winInOneMap)))
+	want := game.Move{Column: 5, Direction: game.DirectionDown}
+	if move, err := bot.ChooseMove(g); err != nil || move != want {
+		t.Errorf("Bot should play the winning move %v, got %v (%v)", want, move, err)
+	}
+	
+	// Away from the endgame the fallback bot plays
+	g = game.NewGameWithSeed(game.DefaultConfig(), game.UniformGenerator{}, 1)
+	move, err := bot.ChooseMove(g)
+	if err != nil {
+		t.Fatalf("ChooseMove failed: %v", err)
+	}
+	if err := g.ApplyMove(move); err != nil {
+		t.Errorf("Fallback move %v should be legal: %v", move, err)
+	}
+	if bot.Solver.Table.Len() == 0 {
+		t.Errorf("Solver should have kept the solved endgame")
+	}
+}
+
+func TestSolverProbesInBackground(t *testing.T) {
+	solver := NewSolver(NewTable())
+	state := loadState(t, readMap(t, winInOneMap))
+	if _, ok := solver.ProbeInBackground(state); ok || !solver.Solving() {
+		t.Fatalf("An unsolved endgame should start solving in the background")
+	}
+	
+	deadline := time.Now().Add(10 * time.Second)
+	for !solver.Collect() {
+		if time.Now().After(deadline) {
+			t.Fatalf("The background solve never finished")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if solver.Solving() {
+		t.Errorf("Solver should be idle once the result is collected")
+	}
+	if result, ok := solver.ProbeInBackground(state); !ok || result != (Result{Outcome: Win, Plies: 1}) {
+		t.Errorf("Collected endgame should be a win in 1, got %+v (found %v)", result, ok)
+	}
+}
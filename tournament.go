@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"text/tabwriter"
+
+	"micemen/game"
+	"micemen/tournament"
+)
+
+// runTournament handles "micemen tournament [flags] <bot> <bot>...": bot-vs-bot matches
+// with an Elo table at the end
+func runTournament(args []string) error {
+	fs := flag.NewFlagSet("tournament", flag.ExitOnError)
+	opts := addGameFlags(fs)
+	mode := fs.String("mode", tournament.RoundRobin.String(), "round-robin, or gauntlet to pit the first bot against each of the others")
+	games := fs.Int("games", 10, "games per pair of bots, alternating colors")
+	workers := fs.Int("workers", runtime.NumCPU(), "games to play in parallel")
+	maxMoves := fs.Int("max-moves", tournament.DefaultMaxMoves, "moves before a game is scored as a draw")
+	outDir := fs.String("out", "tournament-games", "directory to save each game's record in (empty to not save them)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: micemen tournament [flags] <bot> <bot>...")
+		fmt.Fprintln(fs.Output(), "Bots are given as for -red and -blue, e.g. random alphabeta:3 mcts:500")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < 2 || *games < 1 || *workers < 1 || *maxMoves < 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	tournamentMode, err := tournament.ParseMode(*mode)
+	if err != nil {
+		return err
+	}
+	cfg, gen, err := opts.board()
+	if err != nil {
+		return err
+	}
+	seed := opts.boardSeed()
+
+	bots := fs.Args()
+	results, err := tournament.Run(tournament.Options{
+		Bots:      bots,
+		Mode:      tournamentMode,
+		Games:     *games,
+		Config:    cfg,
+		Generator: gen,
+		Seed:      seed,
+		Workers:   *workers,
+		MaxMoves:  *maxMoves,
+		RecordDir: *outDir,
+		OnGame: func(result tournament.Result, done, total int) {
+			fmt.Printf("[%d/%d] %s\n", done, total, describeGame(result))
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s tournament, %d games per pair, boards from seed %d:\n\n", tournamentMode, *games, seed)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Rank\tBot\tElo\t95% CI\tGames\tW-D-L\tScore")
+	for i, s := range tournament.Standings(bots, results) {
+		fmt.Fprintf(tw, "%d\t%s\t%.0f\t±%.0f\t%d\t%d-%d-%d\t%.1f%%\n",
+			i+1, s.Bot, s.Elo, s.Margin, s.Games, s.Wins, s.Draws, s.Losses, 100*s.ScoreRate())
+	}
+	tw.Flush()
+
+	if *outDir != "" {
+		fmt.Printf("\nGame records saved in %s\n", *outDir)
+	}
+	return nil
+}
+
+// describeGame sums up a finished tournament game in one line
+func describeGame(r tournament.Result) string {
+	players := fmt.Sprintf("game %d, %s (Red) vs %s (Blue) on board %d", r.Number, r.Red, r.Blue, r.Seed)
+	switch r.Winner {
+	case game.Red:
+		return fmt.Sprintf("%s: %s won in %d moves", players, r.Red, r.Moves)
+	case game.Blue:
+		return fmt.Sprintf("%s: %s won in %d moves", players, r.Blue, r.Moves)
+	default:
+		return fmt.Sprintf("%s: draw after %d moves", players, r.Moves)
+	}
+}
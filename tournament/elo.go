@@ -0,0 +1,129 @@
+package tournament
+
+import (
+	"math"
+	"slices"
+)
+
+// Rating settings
+const (
+	MeanRating = 1500 // Ratings are shifted so the field averages this
+	confidence = 1.96 // Standard errors either side of a rating for a 95% interval
+	fitRounds  = 10000
+)
+
+// eloScale converts natural-log strengths to Elo points
+const eloScale = 400 / math.Ln10
+
+// Standing is a bot's record and rating over the tournament
+type Standing struct {
+	Bot                 string
+	Games               int
+	Wins, Draws, Losses int
+	Score               float64 // Points scored, counting draws as half
+	Elo                 float64
+	Margin              float64 // Half width of the 95% confidence interval around Elo
+}
+
+// ScoreRate returns the share of the points available that the bot scored
+func (s Standing) ScoreRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return s.Score / float64(s.Games)
+}
+
+// Standings rates the bots from the results, best first. Ratings are the maximum
+// likelihood fit of the Elo model to every game played, so a bot's rating accounts for
+// the strength of the bots it met. Each bot also gets one virtual draw against an average
+// opponent, which keeps the ratings finite for bots that won or lost every game.
+func Standings(bots []string, results []Result) []Standing {
+	n := len(bots)
+	index := make(map[string]int, n)
+	for i, bot := range bots {
+		index[bot] = i
+	}
+
+	standings := make([]Standing, n)
+	games := make([][]float64, n) // games[i][j]: games between bots i and j
+	for i, bot := range bots {
+		standings[i].Bot = bot
+		games[i] = make([]float64, n)
+	}
+	for _, result := range results {
+		red, blue := index[result.Red], index[result.Blue]
+		games[red][blue]++
+		games[blue][red]++
+		for _, i := range []int{red, blue} {
+			s := &standings[i]
+			score, _ := result.Score(s.Bot)
+			s.Games++
+			s.Score += score
+			switch score {
+			case 1:
+				s.Wins++
+			case 0.5:
+				s.Draws++
+			default:
+				s.Losses++
+			}
+		}
+	}
+
+	// Minorization-maximization on the strengths gamma = 10^(Elo/400), with the virtual
+	// opponent's strength fixed at 1
+	gamma := make([]float64, n)
+	for i := range gamma {
+		gamma[i] = 1
+	}
+	for round := 0; round < fitRounds; round++ {
+		change := 0.0
+		for i := range gamma {
+			denom := 1 / (gamma[i] + 1)
+			for j := range gamma {
+				if games[i][j] > 0 {
+					denom += games[i][j] / (gamma[i] + gamma[j])
+				}
+			}
+			next := (standings[i].Score + 0.5) / denom
+			change = max(change, math.Abs(math.Log(next/gamma[i])))
+			gamma[i] = next
+		}
+		if change < 1e-10 {
+			break
+		}
+	}
+
+	// Standard errors from the curvature of the likelihood at the fit
+	mean := 0.0
+	for i := range standings {
+		s := &standings[i]
+		s.Elo = eloScale * math.Log(gamma[i])
+		mean += s.Elo
+
+		p := gamma[i] / (gamma[i] + 1)
+		information := p * (1 - p)
+		for j := range gamma {
+			if games[i][j] > 0 {
+				p := gamma[i] / (gamma[i] + gamma[j])
+				information += games[i][j] * p * (1 - p)
+			}
+		}
+		s.Margin = confidence * eloScale / math.Sqrt(information)
+	}
+	mean /= float64(n)
+	for i := range standings {
+		standings[i].Elo += MeanRating - mean
+	}
+
+	slices.SortStableFunc(standings, func(a, b Standing) int {
+		switch {
+		case a.Elo > b.Elo:
+			return -1
+		case a.Elo < b.Elo:
+			return 1
+		}
+		return 0
+	})
+	return standings
+}
@@ -0,0 +1,256 @@
+// Package tournament plays bots against each other on seeded boards and rates them
+package tournament
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"micemen/ai"
+	"micemen/game"
+	"micemen/record"
+)
+
+// DefaultMaxMoves is how long a game may run before it's scored as a draw
+const DefaultMaxMoves = 400
+
+// Mode is how bots are paired up
+type Mode int
+
+const (
+	RoundRobin Mode = iota // Every bot plays every other bot
+	Gauntlet               // The first bot plays each of the others
+)
+
+// String returns the mode's name as given on the command line
+func (m Mode) String() string {
+	if m == Gauntlet {
+		return "gauntlet"
+	}
+	return "round-robin"
+}
+
+// ParseMode parses a mode name
+func ParseMode(name string) (Mode, error) {
+	for _, mode := range []Mode{RoundRobin, Gauntlet} {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+	return RoundRobin, fmt.Errorf("unknown tournament mode %q (want round-robin or gauntlet)", name)
+}
+
+// Options describes a tournament
+type Options struct {
+	Bots  []string // Bots taking part, as given to ai.New
+	Mode  Mode
+	Games int // Games per pair of bots; an even number gives each side both colors on every board
+
+	Config    game.Config
+	Generator game.BoardGenerator
+	Seed      int64 // Seed of the first board; each further board uses the next seed
+
+	Workers   int    // Games played in parallel
+	MaxMoves  int    // Moves before a game is scored as a draw
+	RecordDir string // Directory each game's record is saved in, or "" to not save them
+
+	// OnGame, if set, is called after each game with the number finished so far
+	OnGame func(result Result, done, total int)
+}
+
+// Match is one game of the tournament
+type Match struct {
+	Number int // From 1, in schedule order
+	Red    string
+	Blue   string
+	Seed   int64
+}
+
+// Result is how a match went
+type Result struct {
+	Match
+	Winner game.PlayerColor // NoPlayer for a draw
+	Moves  int
+	Record string // Path of the saved record, if any
+}
+
+// Score returns the bot's points from the game: 1 for a win, 0.5 for a draw and 0 for a
+// loss, or false if the bot didn't play in it
+func (r Result) Score(bot string) (float64, bool) {
+	var color game.PlayerColor
+	switch bot {
+	case r.Red:
+		color = game.Red
+	case r.Blue:
+		color = game.Blue
+	default:
+		return 0, false
+	}
+
+	switch r.Winner {
+	case color:
+		return 1, true
+	case game.NoPlayer:
+		return 0.5, true
+	default:
+		return 0, true
+	}
+}
+
+// Schedule lists the tournament's games. Each pair plays on boards Seed, Seed+1, ...
+// with colors swapped every game, so with an even number of games both bots play each
+// board from both sides.
+func Schedule(opts Options) []Match {
+	var pairs [][2]string
+	for i, a := range opts.Bots {
+		for _, b := range opts.Bots[i+1:] {
+			pairs = append(pairs, [2]string{a, b})
+		}
+		if opts.Mode == Gauntlet {
+			break
+		}
+	}
+
+	var matches []Match
+	for _, pair := range pairs {
+		for i := 0; i < opts.Games; i++ {
+			red, blue := pair[0], pair[1]
+			if i%2 == 1 {
+				red, blue = blue, red
+			}
+			matches = append(matches, Match{
+				Number: len(matches) + 1,
+				Red:    red,
+				Blue:   blue,
+				Seed:   opts.Seed + int64(i/2),
+			})
+		}
+	}
+	return matches
+}
+
+// Run plays the tournament and returns the results in schedule order
+func Run(opts Options) ([]Result, error) {
+	if len(opts.Bots) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least two bots")
+	}
+	if opts.Games < 1 {
+		return nil, fmt.Errorf("each pair needs at least one game")
+	}
+	seen := make(map[string]bool)
+	for _, name := range opts.Bots {
+		if seen[name] {
+			return nil, fmt.Errorf("bot %q is entered twice", name)
+		}
+		seen[name] = true
+		if _, err := ai.New(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.MaxMoves < 1 {
+		opts.MaxMoves = DefaultMaxMoves
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if opts.RecordDir != "" {
+		if err := os.MkdirAll(opts.RecordDir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := Schedule(opts)
+	queue := make(chan Match, len(matches))
+	for _, match := range matches {
+		queue <- match
+	}
+	close(queue)
+
+	results := make([]Result, len(matches))
+	var mu sync.Mutex
+	var firstErr error
+	done := 0
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for match := range queue {
+				result, err := play(match, opts, workers > 1)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("game %d (%s vs %s): %w", match.Number, match.Red, match.Blue, err)
+				}
+				results[match.Number-1] = result
+				done++
+				if err == nil && opts.OnGame != nil {
+					opts.OnGame(result, done, len(matches))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// play plays one match with fresh bots. When games run in parallel, searching bots get a
+// single thread each so the games share the CPUs fairly.
+func play(match Match, opts Options, parallel bool) (Result, error) {
+	result := Result{Match: match, Winner: game.NoPlayer}
+
+	var bots [2]ai.Bot
+	for color, name := range [2]string{game.Red: match.Red, game.Blue: match.Blue} {
+		bot, err := ai.New(name)
+		if err != nil {
+			return result, err
+		}
+		if mcts, ok := bot.(*ai.MCTSBot); ok && parallel {
+			mcts.Workers = 1
+		}
+		bots[color] = bot
+	}
+
+	g := game.NewGameWithSeed(opts.Config, opts.Generator, match.Seed)
+	for result.Moves < opts.MaxMoves && !g.IsGameOver() {
+		bot := bots[g.GetState().CurrentPlayer]
+		move, err := bot.ChooseMove(g)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", bot.Name(), err)
+		}
+		if err := g.ApplyMove(move); err != nil {
+			return result, fmt.Errorf("%s played %s: %w", bot.Name(), move, err)
+		}
+		result.Moves++
+	}
+	if g.IsGameOver() {
+		result.Winner = g.GetState().Winner
+	}
+
+	if opts.RecordDir != "" {
+		rec := record.FromGame(g, match.Red, match.Blue)
+		rec.Event = fmt.Sprintf("Tournament (%s), game %d", opts.Mode, match.Number)
+		name := fmt.Sprintf("%03d-%s-vs-%s.txt", match.Number, fileSafe(match.Red), fileSafe(match.Blue))
+		result.Record = filepath.Join(opts.RecordDir, name)
+		if err := record.Save(result.Record, rec); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// fileSafe turns a bot name such as "mcts:2s" into something usable in a file name
+func fileSafe(name string) string {
+	return strings.NewReplacer(":", "-", "/", "-", "\\", "-", " ", "-").Replace(name)
+}
@@ -0,0 +1,158 @@
+package tournament
+
+import (
+	"math"
+	"testing"
+
+	"micemen/game"
+	"micemen/record"
+)
+
+func TestScheduleAlternatesColors(t *testing.T) {
+	opts := Options{Bots: []string{"a", "b", "c"}, Games: 4, Seed: 10}
+	matches := Schedule(opts)
+	if len(matches) != 3*4 {
+		t.Fatalf("Round robin of 3 bots should have 12 games, got %d", len(matches))
+	}
+	
+	// Every pair plays each board once from each side
+	played := make(map[[3]any]int)
+	for i, m := range matches {
+		if m.Number != i+1 {
+			t.Errorf("Game %d should be numbered %d, got %d", i, i+1, m.Number)
+		}
+		if m.Seed < 10 || m.Seed > 11 {
+			t.Errorf("Game %d should be on board 10 or 11, got %d", m.Number, m.Seed)
+		}
+		played[[3]any{m.Red, m.Blue, m.Seed}]++
+	}
+	for _, pair := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "c"}} {
+		for seed := int64(10); seed <= 11; seed++ {
+			if played[[3]any{pair[0], pair[1], seed}] != 1 || played[[3]any{pair[1], pair[0], seed}] != 1 {
+				t.Errorf("%s and %s should play board %d once with each color", pair[0], pair[1], seed)
+			}
+		}
+	}
+	
+	opts.Mode = Gauntlet
+	for _, m := range Schedule(opts) {
+		if m.Red != "a" && m.Blue != "a" {
+			t.Errorf("Gauntlet games should all involve the first bot, got %s vs %s", m.Red, m.Blue)
+		}
+	}
+	if n := len(Schedule(opts)); n != 2*4 {
+		t.Errorf("Gauntlet of 3 bots should have 8 games, got %d", n)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, mode := range []Mode{RoundRobin, Gauntlet} {
+		if got, err := ParseMode(mode.String()); err != nil || got != mode {
+			t.Errorf("ParseMode(%q) should give %v, got %v (%v)", mode.String(), mode, got, err)
+		}
+	}
+	if _, err := ParseMode("swiss"); err == nil {
+		t.Errorf("ParseMode should reject an unknown mode")
+	}
+}
+
+func TestRunSavesReplayableRecords(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{
+		Bots:      []string{"random", "alphabeta:1"},
+		Games:     2,
+		Config:    game.NewConfig(11, 7, 0),
+		Generator: game.UniformGenerator{},
+		Seed:      3,
+		Workers:   2,
+		RecordDir: dir,
+	}
+	results, err := Run(opts)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Run should play 2 games, got %d", len(results))
+	}
+	
+	for _, r := range results {
+		rec, err := record.Load(r.Record)
+		if err != nil {
+			t.Fatalf("Loading the record of game %d failed: %v", r.Number, err)
+		}
+		g, err := rec.Replay()
+		if err != nil {
+			t.Fatalf("Replaying game %d failed: %v", r.Number, err)
+		}
+		if rec.Red != r.Red || rec.Blue != r.Blue || len(rec.Moves) != r.Moves {
+			t.Errorf("Record of game %d doesn't match its result", r.Number)
+		}
+		if state := g.GetState(); state.GameOver && state.Winner != r.Winner {
+			t.Errorf("Replaying game %d should give %s the win, got %s", r.Number, r.Winner, state.Winner)
+		}
+	}
+	
+	if _, err := Run(Options{Bots: []string{"random", "random"}, Games: 1}); err == nil {
+		t.Errorf("Run should reject a bot entered twice")
+	}
+	if _, err := Run(Options{Bots: []string{"random", "nobody"}, Games: 1}); err == nil {
+		t.Errorf("Run should reject an unknown bot")
+	}
+}
+
+// results makes n games between two bots, of which a wins the given number and the rest
+// are draws
+func results(a, b string, n, wins int) []Result {
+	var out []Result
+	for i := 0; i < n; i++ {
+		r := Result{Match: Match{Red: a, Blue: b}, Winner: game.NoPlayer}
+		if i < wins {
+			r.Winner = game.Red
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestStandingsRateStrongerBotsHigher(t *testing.T) {
+	bots := []string{"weak", "middle", "strong"}
+	var games []Result
+	games = append(games, results("strong", "middle", 20, 12)...)
+	games = append(games, results("middle", "weak", 20, 12)...)
+	games = append(games, results("strong", "weak", 20, 20)...)
+	
+	standings := Standings(bots, games)
+	for i, want := range []string{"strong", "middle", "weak"} {
+		if standings[i].Bot != want {
+			t.Errorf("Rank %d should be %s, got %s", i+1, want, standings[i].Bot)
+		}
+	}
+	
+	mean := 0.0
+	for _, s := range standings {
+		mean += s.Elo
+		if math.IsInf(s.Elo, 0) || math.IsNaN(s.Elo) || s.Margin <= 0 {
+			t.Errorf("%s should have a finite rating and margin, got %.1f ±%.1f", s.Bot, s.Elo, s.Margin)
+		}
+		if s.Games != 40 {
+			t.Errorf("%s should have played 40 games, got %d", s.Bot, s.Games)
+		}
+	}
+	if mean /= 3; math.Abs(mean-MeanRating) > 1e-6 {
+		t.Errorf("Ratings should average %d, got %.3f", MeanRating, mean)
+	}
+}
+
+func TestStandingsMarginShrinksWithGames(t *testing.T) {
+	bots := []string{"a", "b"}
+	few := Standings(bots, results("a", "b", 4, 2))
+	many := Standings(bots, results("a", "b", 400, 200))
+	
+	// Half wins and half draws is a 75% score, about 190 Elo between the two
+	if diff := many[0].Elo - many[1].Elo; math.Abs(diff-191) > 5 {
+		t.Errorf("A 75%% score should be about 191 Elo ahead, got %.1f", diff)
+	}
+	if many[0].Margin >= few[0].Margin {
+		t.Errorf("More games should narrow the confidence interval, got ±%.1f from 400 games and ±%.1f from 4", many[0].Margin, few[0].Margin)
+	}
+}